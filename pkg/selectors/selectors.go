@@ -0,0 +1,193 @@
+// Package selectors implements a Kubernetes-style label/field selector
+// grammar: comma-separated requirements supporting `=`, `!=`, `in (...)`,
+// `notin (...)`, `exists` (bare key), and `!exists` (`!key`). It is shared
+// across the meshmodel label selector (`?labelSelector=`) and field
+// selector (`?fieldSelector=`) query parameters so both surfaces parse and
+// match the same way.
+package selectors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operator is the comparison a Requirement applies to a key's value.
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!exists"
+)
+
+// Requirement is a single parsed clause of a selector expression, e.g.
+// `tier=prod`, `!deprecated`, or `kind in (Service,Workload)`.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Parse parses a comma-separated selector expression into an ordered slice
+// of Requirements. An empty string parses to a nil slice that matches
+// everything.
+func Parse(raw string) ([]Requirement, error) {
+	var reqs []Requirement
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return reqs, nil
+	}
+
+	for _, clause := range splitTopLevel(raw) {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		req, err := parseRequirement(clause)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+
+	return reqs, nil
+}
+
+// splitTopLevel splits on commas that are not inside a `(...)` value list,
+// so `kind in (Service,Workload),tier=prod` splits into two clauses.
+func splitTopLevel(raw string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i, r := range raw {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, raw[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, raw[start:])
+	return parts
+}
+
+func parseRequirement(clause string) (Requirement, error) {
+	switch {
+	case strings.Contains(clause, "!="):
+		kv := strings.SplitN(clause, "!=", 2)
+		return Requirement{Key: strings.TrimSpace(kv[0]), Operator: NotEquals, Values: []string{strings.TrimSpace(kv[1])}}, nil
+
+	case strings.Contains(clause, "=="):
+		kv := strings.SplitN(clause, "==", 2)
+		return Requirement{Key: strings.TrimSpace(kv[0]), Operator: Equals, Values: []string{strings.TrimSpace(kv[1])}}, nil
+
+	case strings.Contains(clause, "="):
+		kv := strings.SplitN(clause, "=", 2)
+		return Requirement{Key: strings.TrimSpace(kv[0]), Operator: Equals, Values: []string{strings.TrimSpace(kv[1])}}, nil
+
+	case strings.Contains(clause, " notin "):
+		return parseSetRequirement(clause, " notin ", NotIn)
+
+	case strings.Contains(clause, " in "):
+		return parseSetRequirement(clause, " in ", In)
+
+	case strings.HasPrefix(strings.TrimSpace(clause), "!"):
+		return Requirement{Key: strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(clause), "!")), Operator: DoesNotExist}, nil
+
+	default:
+		key := strings.TrimSpace(clause)
+		if key == "" {
+			return Requirement{}, fmt.Errorf("empty selector clause")
+		}
+		return Requirement{Key: key, Operator: Exists}, nil
+	}
+}
+
+func parseSetRequirement(clause, sep string, op Operator) (Requirement, error) {
+	kv := strings.SplitN(clause, sep, 2)
+	key := strings.TrimSpace(kv[0])
+	valueList := strings.TrimSpace(kv[1])
+	valueList = strings.TrimPrefix(valueList, "(")
+	valueList = strings.TrimSuffix(valueList, ")")
+
+	var values []string
+	for _, v := range strings.Split(valueList, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return Requirement{}, fmt.Errorf("selector %q: %s requires at least one value", clause, op)
+	}
+
+	return Requirement{Key: key, Operator: op, Values: values}, nil
+}
+
+// Matches reports whether fields (a flattened key/value view of an entity's
+// metadata or first-class fields) satisfies every Requirement. An empty or
+// nil reqs matches everything.
+func Matches(reqs []Requirement, fields map[string]string) bool {
+	for _, req := range reqs {
+		if !req.matches(fields) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Requirement) matches(fields map[string]string) bool {
+	value, present := fields[r.Key]
+	switch r.Operator {
+	case Exists:
+		return present
+	case DoesNotExist:
+		return !present
+	case Equals:
+		return present && value == r.Values[0]
+	case NotEquals:
+		return !present || value != r.Values[0]
+	case In:
+		return present && contains(r.Values, value)
+	case NotIn:
+		return !present || !contains(r.Values, value)
+	default:
+		return false
+	}
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders req back into selector syntax, e.g. `tier=prod` or
+// `kind in (Service,Workload)`.
+func (r Requirement) String() string {
+	switch r.Operator {
+	case Exists:
+		return r.Key
+	case DoesNotExist:
+		return "!" + r.Key
+	case Equals:
+		return fmt.Sprintf("%s=%s", r.Key, r.Values[0])
+	case NotEquals:
+		return fmt.Sprintf("%s!=%s", r.Key, r.Values[0])
+	case In, NotIn:
+		return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ","))
+	default:
+		return r.Key
+	}
+}
@@ -0,0 +1,178 @@
+package selectors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []Requirement
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			raw:  "",
+			want: nil,
+		},
+		{
+			name: "equals",
+			raw:  "tier=prod",
+			want: []Requirement{{Key: "tier", Operator: Equals, Values: []string{"prod"}}},
+		},
+		{
+			name: "not equals",
+			raw:  "tier!=prod",
+			want: []Requirement{{Key: "tier", Operator: NotEquals, Values: []string{"prod"}}},
+		},
+		{
+			name: "exists",
+			raw:  "tier",
+			want: []Requirement{{Key: "tier", Operator: Exists}},
+		},
+		{
+			name: "does not exist",
+			raw:  "!deprecated",
+			want: []Requirement{{Key: "deprecated", Operator: DoesNotExist}},
+		},
+		{
+			name: "in",
+			raw:  "kind in (Service,Workload)",
+			want: []Requirement{{Key: "kind", Operator: In, Values: []string{"Service", "Workload"}}},
+		},
+		{
+			name: "notin",
+			raw:  "kind notin (Service, Workload)",
+			want: []Requirement{{Key: "kind", Operator: NotIn, Values: []string{"Service", "Workload"}}},
+		},
+		{
+			name: "multiple comma-separated clauses",
+			raw:  "tier=prod,!deprecated,kind in (Service,Workload)",
+			want: []Requirement{
+				{Key: "tier", Operator: Equals, Values: []string{"prod"}},
+				{Key: "deprecated", Operator: DoesNotExist},
+				{Key: "kind", Operator: In, Values: []string{"Service", "Workload"}},
+			},
+		},
+		{
+			name:    "empty clause",
+			raw:     "tier=prod,,kind=Service",
+			want:    []Requirement{{Key: "tier", Operator: Equals, Values: []string{"prod"}}, {Key: "kind", Operator: Equals, Values: []string{"Service"}}},
+			wantErr: false,
+		},
+		{
+			name:    "in with no values",
+			raw:     "kind in ()",
+			wantErr: true,
+		},
+		{
+			name:    "whitespace only",
+			raw:     "  ",
+			want:    nil,
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q): expected error, got none", tt.raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): unexpected error: %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	reqs, err := Parse("tier=prod,!deprecated,kind in (Service,Workload)")
+	if err != nil {
+		t.Fatalf("Parse: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		fields map[string]string
+		want   bool
+	}{
+		{
+			name:   "matches all requirements",
+			fields: map[string]string{"tier": "prod", "kind": "Service"},
+			want:   true,
+		},
+		{
+			name:   "wrong tier",
+			fields: map[string]string{"tier": "staging", "kind": "Service"},
+			want:   false,
+		},
+		{
+			name:   "has deprecated key",
+			fields: map[string]string{"tier": "prod", "kind": "Service", "deprecated": "true"},
+			want:   false,
+		},
+		{
+			name:   "kind not in set",
+			fields: map[string]string{"tier": "prod", "kind": "Other"},
+			want:   false,
+		},
+		{
+			name:   "missing required key",
+			fields: map[string]string{"tier": "prod"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Matches(reqs, tt.fields); got != tt.want {
+				t.Errorf("Matches(%v) = %v, want %v", tt.fields, got, tt.want)
+			}
+		})
+	}
+
+	if !Matches(nil, map[string]string{"anything": "goes"}) {
+		t.Error("Matches with nil requirements should match everything")
+	}
+}
+
+func TestRequirementStringRoundTrip(t *testing.T) {
+	exprs := []string{
+		"tier=prod",
+		"tier!=prod",
+		"!deprecated",
+		"deprecated",
+		"kind in (Service,Workload)",
+		"kind notin (Service,Workload)",
+	}
+
+	for _, expr := range exprs {
+		t.Run(expr, func(t *testing.T) {
+			reqs, err := Parse(expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): unexpected error: %v", expr, err)
+			}
+			if len(reqs) != 1 {
+				t.Fatalf("Parse(%q): expected 1 requirement, got %d", expr, len(reqs))
+			}
+
+			got := reqs[0].String()
+			reparsed, err := Parse(got)
+			if err != nil {
+				t.Fatalf("Parse(%q) (round-trip of %q): unexpected error: %v", got, expr, err)
+			}
+			if !reflect.DeepEqual(reqs, reparsed) {
+				t.Errorf("round-trip mismatch for %q: String() = %q, reparsed = %+v, original = %+v", expr, got, reparsed, reqs)
+			}
+		})
+	}
+}
@@ -2,20 +2,27 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	stdsort "sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/gorilla/mux"
 
+	"github.com/meshery/meshery/pkg/selectors"
 	"github.com/meshery/meshery/server/helpers"
 	"github.com/meshery/meshery/server/helpers/utils"
 	"github.com/meshery/meshery/server/models"
@@ -46,6 +53,282 @@ import (
 /**Meshmodel endpoints **/
 const DefaultPageSizeForMeshModelComponents = 25
 
+// modelSortAllowList maps the API-facing field names accepted by the new
+// `sort` query grammar (see resolveSortParam) to the DB columns backing
+// meshmodel model listings.
+var modelSortAllowList = map[string]string{
+	"displayName": "display_name",
+	"name":        "name",
+	"category":    "category_name",
+	"version":     "version",
+	"registrant":  "registrant",
+}
+
+// categorySortAllowList is the `sort` allow-list for meshmodel category listings.
+var categorySortAllowList = map[string]string{
+	"name": "name",
+}
+
+// componentSortAllowList is the `sort` allow-list for meshmodel component listings.
+var componentSortAllowList = map[string]string{
+	"displayName": "display_name",
+	"name":        "name",
+	"model":       "model_name",
+	"category":    "category_name",
+	"version":     "version",
+	"apiVersion":  "api_version",
+}
+
+// isLegacySortDirection reports whether raw is empty or one of the old
+// `sort=asc|desc` values, as opposed to the new comma-separated field list
+// grammar (e.g. `sort=category,-version`).
+func isLegacySortDirection(raw string) bool {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "asc", "desc":
+		return true
+	default:
+		return false
+	}
+}
+
+// resolveSortParam parses the `sort` query parameter using the new
+// comma-separated, `-`-prefixed field grammar (e.g. `sort=category,-version`)
+// against allowed. When raw is empty or one of the legacy `asc`/`desc`
+// values, the caller's existing order/sortDir (derived from the deprecated
+// `order`+`sort` pair via getPaginationParams) are returned unchanged, and
+// fields is nil.
+//
+// The registry's SQL builder only emits a single ORDER BY column, so order/
+// sortDir - built from fields[0] - are what it's queried with regardless of
+// how many fields were parsed; the full fields slice is also returned so a
+// caller with more than one can apply the rest as an in-process multi-key
+// sort via sortEntitiesByFields once it has the full result set, breaking
+// ties fields[0] alone would leave unresolved. Callers should treat a parse
+// error as a 400, since an unknown field is almost always a client typo
+// rather than something safe to ignore.
+func resolveSortParam(raw string, allowed map[string]string, order, sortDir string) (string, string, []helpers.SortField, error) {
+	if isLegacySortDirection(raw) {
+		return order, sortDir, nil, nil
+	}
+
+	fields, err := helpers.ParseSort(raw, allowed)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if len(fields) == 0 {
+		return order, sortDir, nil, nil
+	}
+
+	sortDir = "asc"
+	if fields[0].Descending {
+		sortDir = "desc"
+	}
+	return fields[0].Field, sortDir, fields, nil
+}
+
+// parseComponentSelectors parses the `labelSelector` and `fieldSelector`
+// query parameters (see pkg/selectors) into the Requirement slices
+// filterEntitiesBySelectors matches entities against, the same way
+// matchesWatchEvent does for the watch endpoints. regv1beta1.ComponentFilter
+// has no selector fields of its own, so the registry can't push this down;
+// labelSelector matches against a component's and its model's `metadata`
+// maps, fieldSelector against first-class fields such as `metadata.status`
+// and `spec.registrant`. Both are optional; an empty query string parses to
+// a nil slice that matches everything. A malformed expression is a client
+// error, since an unparsable selector is almost always a typo rather than
+// something safe to ignore.
+func parseComponentSelectors(queryParams url.Values) (labelSelector, fieldSelector []selectors.Requirement, err error) {
+	labelSelector, err = selectors.Parse(queryParams.Get("labelSelector"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("labelSelector: %w", err)
+	}
+	fieldSelector, err = selectors.Parse(queryParams.Get("fieldSelector"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("fieldSelector: %w", err)
+	}
+	return labelSelector, fieldSelector, nil
+}
+
+// filterEntitiesBySelectors narrows entities down to those matching
+// labelSelector and fieldSelector, applying both the same way watch
+// filtering does: labelSelector is tested only against an entity's
+// `metadata.*` fields and its embedded model's `model.metadata.*` fields,
+// fieldSelector against its full flattened form (see
+// flattenJSONFields/metadataFields in meshmodel_watch.go). It is a no-op,
+// returning entities unchanged, when both selectors are empty.
+func filterEntitiesBySelectors(entities []entity.Entity, labelSelector, fieldSelector []selectors.Requirement) []entity.Entity {
+	if len(labelSelector) == 0 && len(fieldSelector) == 0 {
+		return entities
+	}
+	filtered := make([]entity.Entity, 0, len(entities))
+	for _, e := range entities {
+		fields := flattenJSONFields(e)
+		if len(labelSelector) > 0 && !selectors.Matches(labelSelector, metadataFields(fields)) {
+			continue
+		}
+		if len(fieldSelector) > 0 && !selectors.Matches(fieldSelector, fields) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+// resolveFetchBounds picks the offset/limit to actually query the registry
+// with. Some narrowing - label/field selectors (filterEntitiesBySelectors) or
+// a real multi-key sort (sortEntitiesByFields) - only happens in process
+// after the fetch, since neither is something the registry can do itself; in
+// that case needsFullFetch is true, this fetches everything (0, 0), and
+// paginateEntities applies the real offset/limit afterward. Otherwise the
+// registry's own offset/limit is used unchanged and no client-side
+// pagination is needed.
+func resolveFetchBounds(offset, limit int, needsFullFetch bool) (fetchOffset, fetchLimit int) {
+	if needsFullFetch {
+		return 0, 0
+	}
+	return offset, limit
+}
+
+// selectorFetchBounds is resolveFetchBounds scoped to label/field selectors.
+func selectorFetchBounds(offset, limit int, labelSelector, fieldSelector []selectors.Requirement) (fetchOffset, fetchLimit int) {
+	return resolveFetchBounds(offset, limit, len(labelSelector) > 0 || len(fieldSelector) > 0)
+}
+
+// paginateEntities applies offset/limit to entities that were narrowed in
+// process after an unpaginated fetch (see resolveFetchBounds), and is a
+// no-op otherwise.
+func paginateEntities(entities []entity.Entity, offset, limit int, needsFullFetch bool) []entity.Entity {
+	if !needsFullFetch {
+		return entities
+	}
+	if offset >= len(entities) {
+		return nil
+	}
+	end := len(entities)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return entities[offset:end]
+}
+
+// paginateFilteredEntities is paginateEntities scoped to label/field
+// selectors.
+func paginateFilteredEntities(entities []entity.Entity, offset, limit int, labelSelector, fieldSelector []selectors.Requirement) []entity.Entity {
+	return paginateEntities(entities, offset, limit, len(labelSelector) > 0 || len(fieldSelector) > 0)
+}
+
+// sortFieldJSONKey maps the sort allow-lists' API-facing field names to the
+// JSON key sortEntitiesByFields reads off each entity, the same
+// find-regardless-of-depth convention cardinalityFieldJSONKey uses.
+var sortFieldJSONKey = map[string]string{
+	"displayName": "displayName",
+	"name":        "name",
+	"category":    "categoryName",
+	"version":     "version",
+	"registrant":  "registrant",
+	"model":       "modelName",
+	"apiVersion":  "apiVersion",
+}
+
+// apiSortFieldName reverses allowed (an API-facing name -> DB column map,
+// e.g. modelSortAllowList) back to the API-facing name for column, so
+// sortEntitiesByFields can look it up in sortFieldJSONKey - ParseSort only
+// hands back the resolved DB column.
+func apiSortFieldName(allowed map[string]string, column string) string {
+	for api, col := range allowed {
+		if col == column {
+			return api
+		}
+	}
+	return column
+}
+
+// sortEntitiesByFields performs a real multi-key ORDER BY in process: the
+// registry's SQL builder only emits a single ORDER BY column (see
+// resolveSortParam), so a client's second and later sort fields need to be
+// applied here instead, breaking ties on fields[0] with fields[1], and so on.
+// Entities are walked as generic JSON, the same way filterEntitiesBySelectors
+// does, since a sort field sits at a different nesting depth depending on
+// entity kind.
+func sortEntitiesByFields(entities []entity.Entity, fields []helpers.SortField, allowed map[string]string) {
+	type decodedEntity struct {
+		entity  entity.Entity
+		decoded interface{}
+	}
+
+	ordered := make([]decodedEntity, len(entities))
+	for i, e := range entities {
+		var decoded interface{}
+		if raw, err := json.Marshal(e); err == nil {
+			_ = json.Unmarshal(raw, &decoded)
+		}
+		ordered[i] = decodedEntity{entity: e, decoded: decoded}
+	}
+
+	stdsort.SliceStable(ordered, func(i, j int) bool {
+		for _, f := range fields {
+			jsonKey := sortFieldJSONKey[apiSortFieldName(allowed, f.Field)]
+			vi, _ := findJSONField(ordered[i].decoded, jsonKey)
+			vj, _ := findJSONField(ordered[j].decoded, jsonKey)
+			if vi == vj {
+				continue
+			}
+			if f.Descending {
+				return vi > vj
+			}
+			return vi < vj
+		}
+		return false
+	})
+
+	for i := range ordered {
+		entities[i] = ordered[i].entity
+	}
+}
+
+// requestContext derives a context from the incoming request for a handler
+// to check its own cancellation/deadline against via writeIfRequestCanceled.
+// registryManager's interface is owned by meshkit and takes no context
+// today, so this ctx is NOT plumbed into its calls - it only lets a handler
+// notice, after a registryManager call returns, that the client had already
+// disconnected or that ```?timeout={duration}``` (e.g. `?timeout=5s`) had
+// already elapsed, and respond 499 instead of writing to a response nobody
+// is listening for anymore. The returned cancel func must be deferred by
+// the caller.
+func requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx := r.Context()
+
+	raw := strings.TrimSpace(r.URL.Query().Get("timeout"))
+	if raw == "" {
+		return context.WithCancel(ctx)
+	}
+
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// writeIfRequestCanceled writes an HTTP 499 (client closed request) response
+// and returns true when ctx was canceled or its deadline expired by the time
+// a registryManager call returned err, so a handler can surface "the client
+// gave up" as a distinguishable response instead of falling through to its
+// generic 500 handling. It is a no-op, returning false, for a nil err or any
+// error unrelated to ctx. Since ctx isn't passed into the registryManager
+// call itself (see requestContext), this can only catch cancellation after
+// the fact, not abort an in-flight query early.
+func writeIfRequestCanceled(rw http.ResponseWriter, ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() == nil {
+		return false
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+	http.Error(rw, fmt.Sprintf("request canceled: %s", ctx.Err()), 499)
+	return true
+}
+
 // swagger:route GET /api/meshmodels/categories/{category}/models GetMeshmodelModelsByCategories idGetMeshmodelModelsByCategories
 //
 // Handle GET request for getting all meshmodel models for a given category. The component type/model name should be lowercase like "kubernetes", "istio"
@@ -61,6 +344,8 @@ const DefaultPageSizeForMeshModelComponents = 25
 // ```?page={page-number}``` Default page number is 1
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
+//
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
 // ```?annotations={["true"/"false"/]}``` When this query parameter is "true", only models with the "isAnnotation" property set to true are returned. When  this query parameter is "false", all models except those considered to be annotation models are returned. Any other value of the query parameter results in both annoations as well as non-annotation models being returned.
 // responses:
 // ```?annotations={["true"/"false"/]}``` If "true" models having "isAnnotation" property as true are "only" returned, If false all models except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation models being returned.
@@ -69,6 +354,13 @@ const DefaultPageSizeForMeshModelComponents = 25
 func (h *Handler) GetMeshmodelModelsByCategories(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	cat := mux.Vars(r)["category"]
 	queryParams := r.URL.Query()
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
@@ -87,7 +379,10 @@ func (h *Handler) GetMeshmodelModelsByCategories(rw http.ResponseWriter, r *http
 		filter.Greedy = true
 		filter.DisplayName = search
 	}
-	entities, count, _, _ := h.registryManager.GetEntities(filter)
+	entities, count, _, getErr := h.registryManager.GetEntities(filter)
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
 	var modelDefs []_model.ModelDefinition
 	for _, model := range entities {
 		model, ok := model.(*_model.ModelDefinition)
@@ -110,6 +405,8 @@ func (h *Handler) GetMeshmodelModelsByCategories(rw http.ResponseWriter, r *http
 		Models:   models.FindDuplicateModels(modelDefs),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(res); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -131,6 +428,8 @@ func (h *Handler) GetMeshmodelModelsByCategories(rw http.ResponseWriter, r *http
 // ```?page={page-number}``` Default page number is 1
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
+//
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
 // responses:
 // ```?annotations={["true"/"false"/]}``` If "true" models having "isAnnotation" property as true are "only" returned, If false all models except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation models being returned.
 //
@@ -138,6 +437,13 @@ func (h *Handler) GetMeshmodelModelsByCategories(rw http.ResponseWriter, r *http
 func (h *Handler) GetMeshmodelModelsByCategoriesByModel(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	cat := mux.Vars(r)["category"]
 	model := mux.Vars(r)["model"]
 	queryParams := r.URL.Query()
@@ -148,7 +454,7 @@ func (h *Handler) GetMeshmodelModelsByCategoriesByModel(rw http.ResponseWriter,
 	}
 	returnAnnotationComp := queryParams.Get("annotations")
 
-	entities, count, _, _ := h.registryManager.GetEntities(&regv1beta1.ModelFilter{
+	entities, count, _, getErr := h.registryManager.GetEntities(&regv1beta1.ModelFilter{
 		Category:    cat,
 		Name:        model,
 		Version:     queryParams.Get("version"),
@@ -159,6 +465,9 @@ func (h *Handler) GetMeshmodelModelsByCategoriesByModel(rw http.ResponseWriter,
 		Sort:        sort,
 		Annotations: returnAnnotationComp,
 	})
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
 
 	var modelDefs []_model.ModelDefinition
 	for _, model := range entities {
@@ -182,6 +491,8 @@ func (h *Handler) GetMeshmodelModelsByCategoriesByModel(rw http.ResponseWriter,
 		Models:   models.FindDuplicateModels(modelDefs),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(res); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -195,15 +506,15 @@ func (h *Handler) GetMeshmodelModelsByCategoriesByModel(rw http.ResponseWriter,
 //
 // ```?version={version}``` If version is unspecified then all models are returned
 //
-// ```?order={field}``` orders on the passed field
+// ```?sort={field[,-field2,...]}``` Comma-separated list of fields to order on, each optionally prefixed with "-" for descending (e.g. ```sort=category,-version```). ```?order={field}``` and the asc/desc form of ```?sort=``` are still accepted as a deprecated fallback
 //
 // ```?search={modelname}``` If search is non empty then a greedy search is performed
 //
-// ```?sort={[asc/desc]}``` Default behavior is asc
-//
 // ```?page={page-number}``` Default page number is 1
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
+//
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
 // responses:
 // ```?annotations={["true"/"false"/]}``` If "true" models having "isAnnotation" property as true are "only" returned, If false all models except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation models being returned.
 //
@@ -211,17 +522,31 @@ func (h *Handler) GetMeshmodelModelsByCategoriesByModel(rw http.ResponseWriter,
 func (h *Handler) GetMeshmodelModels(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	queryParams := r.URL.Query()
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 	v := queryParams.Get("version")
 	returnAnnotationComp := queryParams.Get("annotations")
 
+	order, sort, sortFields, err := resolveSortParam(queryParams.Get("sort"), modelSortAllowList, order, sort)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fetchOffset, fetchLimit := resolveFetchBounds(offset, limit, len(sortFields) > 1)
+
 	filter := &regv1beta1.ModelFilter{
 		Id:          queryParams.Get("id"),
 		Registrant:  queryParams.Get("registrant"),
 		Version:     v,
-		Limit:       limit,
-		Offset:      offset,
+		Limit:       fetchLimit,
+		Offset:      fetchOffset,
 		OrderOn:     order,
 		Sort:        sort,
 		Annotations: returnAnnotationComp,
@@ -237,7 +562,17 @@ func (h *Handler) GetMeshmodelModels(rw http.ResponseWriter, r *http.Request) {
 		filter.Greedy = true
 	}
 
-	entities, count, _, _ := h.registryManager.GetEntities(filter)
+	entities, count, _, getErr := h.registryManager.GetEntities(filter)
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+
+	if len(sortFields) > 1 {
+		sortEntitiesByFields(entities, sortFields, modelSortAllowList)
+		count = int64(len(entities))
+	}
+	entities = paginateEntities(entities, offset, limit, len(sortFields) > 1)
+
 	var modelDefs []_model.ModelDefinition
 	for _, model := range entities {
 		model, ok := model.(*_model.ModelDefinition)
@@ -259,6 +594,8 @@ func (h *Handler) GetMeshmodelModels(rw http.ResponseWriter, r *http.Request) {
 		Models:   models.FindDuplicateModels(modelDefs),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(res); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -272,15 +609,15 @@ func (h *Handler) GetMeshmodelModels(rw http.ResponseWriter, r *http.Request) {
 //
 // ```?version={version}``` If version is unspecified then all models are returned
 //
-// ```?order={field}``` orders on the passed field
+// ```?sort={field[,-field2,...]}``` Comma-separated list of fields to order on, each optionally prefixed with "-" for descending (e.g. ```sort=category,-version```). ```?order={field}``` and the asc/desc form of ```?sort=``` are still accepted as a deprecated fallback
 //
 // ```?search={[true/false]}``` If search is true then a greedy search is performed
 //
-// ```?sort={[asc/desc]}``` Default behavior is asc
-//
 // ```?page={page-number}``` Default page number is 1
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
+//
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
 // responses:
 // ```?annotations={["true"/"false"/]}``` If "true" models having "isAnnotation" property as true are "only" returned, If false all models except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation models being returned.
 //
@@ -288,6 +625,13 @@ func (h *Handler) GetMeshmodelModels(rw http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetMeshmodelModelsByName(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	name := mux.Vars(r)["model"]
 	queryParams := r.URL.Query()
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
@@ -297,11 +641,19 @@ func (h *Handler) GetMeshmodelModelsByName(rw http.ResponseWriter, r *http.Reque
 	}
 	v := queryParams.Get("version")
 	returnAnnotationComp := queryParams.Get("annotations")
-	entities, count, _, _ := h.registryManager.GetEntities(&regv1beta1.ModelFilter{
+
+	order, sort, sortFields, err := resolveSortParam(queryParams.Get("sort"), modelSortAllowList, order, sort)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fetchOffset, fetchLimit := resolveFetchBounds(offset, limit, len(sortFields) > 1)
+
+	entities, count, _, getErr := h.registryManager.GetEntities(&regv1beta1.ModelFilter{
 		Name:        name,
 		Version:     v,
-		Limit:       limit,
-		Offset:      offset,
+		Limit:       fetchLimit,
+		Offset:      fetchOffset,
 		Greedy:      greedy,
 		OrderOn:     order,
 		Sort:        sort,
@@ -310,6 +662,15 @@ func (h *Handler) GetMeshmodelModelsByName(rw http.ResponseWriter, r *http.Reque
 		Components:    queryParams.Get("components") == "true",
 		Relationships: queryParams.Get("relationships") == "true",
 	})
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+
+	if len(sortFields) > 1 {
+		sortEntitiesByFields(entities, sortFields, modelSortAllowList)
+		count = int64(len(entities))
+	}
+	entities = paginateEntities(entities, offset, limit, len(sortFields) > 1)
 
 	var modelDefs []_model.ModelDefinition
 	for _, model := range entities {
@@ -333,6 +694,8 @@ func (h *Handler) GetMeshmodelModelsByName(rw http.ResponseWriter, r *http.Reque
 		Models:   models.FindDuplicateModels(modelDefs),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(res); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -342,25 +705,40 @@ func (h *Handler) GetMeshmodelModelsByName(rw http.ResponseWriter, r *http.Reque
 // swagger:route GET /api/meshmodels/categories GetMeshmodelCategories idGetMeshmodelCategories
 // Handle GET request for getting all meshmodel categories
 //
-// ```?order={field}``` orders on the passed field
-//
-// ```?sort={[asc/desc]}``` Default behavior is asc
+// ```?sort={field[,-field2,...]}``` Comma-separated list of fields to order on, each optionally prefixed with "-" for descending. ```?order={field}``` and the asc/desc form of ```?sort=``` are still accepted as a deprecated fallback
 //
 // ```?search={categoryName}``` If search is non empty then a greedy search is performed
 //
 // ```?page={page-number}``` Default page number is 1
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
+//
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
 // responses:
 //
 //	200: []meshmodelCategoriesResponseWrapper
 func (h *Handler) GetMeshmodelCategories(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
+
+	order, sort, sortFields, err := resolveSortParam(r.URL.Query().Get("sort"), categorySortAllowList, order, sort)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fetchOffset, fetchLimit := resolveFetchBounds(offset, limit, len(sortFields) > 1)
+
 	filter := &regv1beta1.CategoryFilter{
-		Limit:   limit,
-		Offset:  offset,
+		Limit:   fetchLimit,
+		Offset:  fetchOffset,
 		OrderOn: order,
 		Sort:    sort,
 	}
@@ -369,7 +747,16 @@ func (h *Handler) GetMeshmodelCategories(rw http.ResponseWriter, r *http.Request
 		filter.Name = search
 	}
 
-	categories, count, _, _ := h.registryManager.GetEntities(filter)
+	categories, count, _, getErr := h.registryManager.GetEntities(filter)
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+
+	if len(sortFields) > 1 {
+		sortEntitiesByFields(categories, sortFields, categorySortAllowList)
+		count = int64(len(categories))
+	}
+	categories = paginateEntities(categories, offset, limit, len(sortFields) > 1)
 
 	var pgSize int64
 
@@ -386,6 +773,8 @@ func (h *Handler) GetMeshmodelCategories(rw http.ResponseWriter, r *http.Request
 		Categories: categories,
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(res); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -403,6 +792,8 @@ func (h *Handler) GetMeshmodelCategories(rw http.ResponseWriter, r *http.Request
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
 //
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
+//
 // ```?search={[true/false]}``` If search is true then a greedy search is performed
 // responses:
 //
@@ -410,13 +801,20 @@ func (h *Handler) GetMeshmodelCategories(rw http.ResponseWriter, r *http.Request
 func (h *Handler) GetMeshmodelCategoriesByName(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 	name := mux.Vars(r)["category"]
 	var greedy bool
 	if search == "true" {
 		greedy = true
 	}
-	categories, count, _, _ := h.registryManager.GetEntities(&regv1beta1.CategoryFilter{
+	categories, count, _, getErr := h.registryManager.GetEntities(&regv1beta1.CategoryFilter{
 		Name:    name,
 		Limit:   limit,
 		Greedy:  greedy,
@@ -424,6 +822,9 @@ func (h *Handler) GetMeshmodelCategoriesByName(rw http.ResponseWriter, r *http.R
 		OrderOn: order,
 		Sort:    sort,
 	})
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
 
 	var pgSize int64
 
@@ -440,6 +841,8 @@ func (h *Handler) GetMeshmodelCategoriesByName(rw http.ResponseWriter, r *http.R
 		Categories: categories,
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(res); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -466,12 +869,25 @@ func (h *Handler) GetMeshmodelCategoriesByName(rw http.ResponseWriter, r *http.R
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
 //
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
+//
 // ```?annotations={["true"/"false"/]}``` If "true" components having "isAnnotation" property as true are "only" returned, If false all components except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation components being returned.
+//
+// ```?labelSelector={expr}``` Kubernetes-style label selector over component/model metadata, e.g. `tier=prod,!deprecated,kind in (Service,Workload)`
+//
+// ```?fieldSelector={expr}``` Kubernetes-style field selector over first-class fields, e.g. `metadata.status=enabled,spec.registrant=kubernetes`
 // responses:
 // 200: []meshmodelComponentsDuplicateResponseWrapper
 func (h *Handler) GetMeshmodelComponentsByNameByModelByCategory(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 	name := mux.Vars(r)["name"]
 
@@ -484,19 +900,34 @@ func (h *Handler) GetMeshmodelComponentsByNameByModelByCategory(rw http.Response
 	cat := mux.Vars(r)["category"]
 	v := queryParams.Get("version")
 	returnAnnotationComp := queryParams.Get("annotations")
-	entities, count, _, _ := h.registryManager.GetEntities(&regv1beta1.ComponentFilter{
+	labelSelector, fieldSelector, err := parseComponentSelectors(queryParams)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fetchOffset, fetchLimit := selectorFetchBounds(offset, limit, labelSelector, fieldSelector)
+	entities, count, _, getErr := h.registryManager.GetEntities(&regv1beta1.ComponentFilter{
 		Name:         name,
 		CategoryName: cat,
 		ModelName:    typ,
 		APIVersion:   queryParams.Get("apiVersion"),
 		Version:      v,
-		Offset:       offset,
+		Offset:       fetchOffset,
 		Greedy:       greedy,
-		Limit:        limit,
+		Limit:        fetchLimit,
 		OrderOn:      order,
 		Sort:         sort,
 		Annotations:  returnAnnotationComp,
 	})
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+
+	entities = filterEntitiesBySelectors(entities, labelSelector, fieldSelector)
+	if len(labelSelector) > 0 || len(fieldSelector) > 0 {
+		count = int64(len(entities))
+	}
+	entities = paginateFilteredEntities(entities, offset, limit, labelSelector, fieldSelector)
 
 	comps := prettifyCompDefSchema(entities)
 
@@ -514,6 +945,8 @@ func (h *Handler) GetMeshmodelComponentsByNameByModelByCategory(rw http.Response
 		Components: models.FindDuplicateComponents(comps),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(response); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -542,13 +975,26 @@ func (h *Handler) GetMeshmodelComponentsByNameByModelByCategory(rw http.Response
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
 //
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
+//
 // ```?annotations={["true"/"false"/]}``` If "true" components having "isAnnotation" property as true are "only" returned, If false all components except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation components being returned.
+//
+// ```?labelSelector={expr}``` Kubernetes-style label selector over component/model metadata, e.g. `tier=prod,!deprecated,kind in (Service,Workload)`
+//
+// ```?fieldSelector={expr}``` Kubernetes-style field selector over first-class fields, e.g. `metadata.status=enabled,spec.registrant=kubernetes`
 // responses:
 //
 //	200: []meshmodelComponentsDuplicateResponseWrapper
 func (h *Handler) GetMeshmodelComponentsByNameByCategory(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 	name := mux.Vars(r)["name"]
 	var greedy bool
@@ -559,20 +1005,35 @@ func (h *Handler) GetMeshmodelComponentsByNameByCategory(rw http.ResponseWriter,
 	cat := mux.Vars(r)["category"]
 	v := queryParams.Get("version")
 	returnAnnotationComp := queryParams.Get("annotations")
+	labelSelector, fieldSelector, err := parseComponentSelectors(queryParams)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fetchOffset, fetchLimit := selectorFetchBounds(offset, limit, labelSelector, fieldSelector)
 
-	entities, count, _, _ := h.registryManager.GetEntities(&regv1beta1.ComponentFilter{
+	entities, count, _, getErr := h.registryManager.GetEntities(&regv1beta1.ComponentFilter{
 		Name:         name,
 		ModelName:    queryParams.Get("model"),
 		CategoryName: cat,
 		APIVersion:   queryParams.Get("apiVersion"),
 		Version:      v,
-		Offset:       offset,
-		Limit:        limit,
+		Offset:       fetchOffset,
+		Limit:        fetchLimit,
 		Greedy:       greedy,
 		OrderOn:      order,
 		Sort:         sort,
 		Annotations:  returnAnnotationComp,
 	})
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+	entities = filterEntitiesBySelectors(entities, labelSelector, fieldSelector)
+	if len(labelSelector) > 0 || len(fieldSelector) > 0 {
+		count = int64(len(entities))
+	}
+	entities = paginateFilteredEntities(entities, offset, limit, labelSelector, fieldSelector)
+
 	comps := prettifyCompDefSchema(entities)
 
 	var pgSize int64
@@ -589,6 +1050,8 @@ func (h *Handler) GetMeshmodelComponentsByNameByCategory(rw http.ResponseWriter,
 		Components: models.FindDuplicateComponents(comps),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(response); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -605,9 +1068,7 @@ func (h *Handler) GetMeshmodelComponentsByNameByCategory(rw http.ResponseWriter,
 //
 // ```?apiVersion={apiVersion}``` If apiVersion is unspecified then all components are returned
 //
-// ```?order={field}``` orders on the passed field
-//
-// ```?sort={[asc/desc]}``` Default behavior is asc
+// ```?sort={field[,-field2,...]}``` Comma-separated list of fields to order on, each optionally prefixed with "-" for descending. ```?order={field}``` and the asc/desc form of ```?sort=``` are still accepted as a deprecated fallback
 //
 // ```?search={[true/false]}``` If search is true then a greedy search is performed
 //
@@ -615,13 +1076,26 @@ func (h *Handler) GetMeshmodelComponentsByNameByCategory(rw http.ResponseWriter,
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
 //
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
+//
 // ```?annotations={["true"/"false"/]}``` If "true" components having "isAnnotation" property as true are "only" returned, If false all components except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation components being returned. returned.
+//
+// ```?labelSelector={expr}``` Kubernetes-style label selector over component/model metadata, e.g. `tier=prod,!deprecated,kind in (Service,Workload)`
+//
+// ```?fieldSelector={expr}``` Kubernetes-style field selector over first-class fields, e.g. `metadata.status=enabled,spec.registrant=kubernetes`
 // responses:
 //
 //	200: []meshmodelComponentsDuplicateResponseWrapper
 func (h *Handler) GetMeshmodelComponentsByNameByModel(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 	name := mux.Vars(r)["name"]
 	var greedy bool
@@ -635,18 +1109,44 @@ func (h *Handler) GetMeshmodelComponentsByNameByModel(rw http.ResponseWriter, r
 
 	returnAnnotationComp := queryParams.Get("annotations")
 
-	entities, count, _, _ := h.registryManager.GetEntities(&regv1beta1.ComponentFilter{
+	order, sort, sortFields, err := resolveSortParam(queryParams.Get("sort"), componentSortAllowList, order, sort)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	labelSelector, fieldSelector, err := parseComponentSelectors(queryParams)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	needsFullFetch := len(labelSelector) > 0 || len(fieldSelector) > 0 || len(sortFields) > 1
+	fetchOffset, fetchLimit := resolveFetchBounds(offset, limit, needsFullFetch)
+
+	entities, count, _, getErr := h.registryManager.GetEntities(&regv1beta1.ComponentFilter{
 		Name:        name,
 		ModelName:   typ,
 		APIVersion:  queryParams.Get("apiVersion"),
 		Version:     v,
-		Offset:      offset,
+		Offset:      fetchOffset,
 		Greedy:      greedy,
-		Limit:       limit,
+		Limit:       fetchLimit,
 		OrderOn:     order,
 		Sort:        sort,
 		Annotations: returnAnnotationComp,
 	})
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+	entities = filterEntitiesBySelectors(entities, labelSelector, fieldSelector)
+	if len(sortFields) > 1 {
+		sortEntitiesByFields(entities, sortFields, componentSortAllowList)
+	}
+	if needsFullFetch {
+		count = int64(len(entities))
+	}
+	entities = paginateEntities(entities, offset, limit, needsFullFetch)
+
 	comps := prettifyCompDefSchema(entities)
 
 	var pgSize int64
@@ -663,6 +1163,8 @@ func (h *Handler) GetMeshmodelComponentsByNameByModel(rw http.ResponseWriter, r
 		Components: models.FindDuplicateComponents(comps),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(response); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -693,12 +1195,25 @@ func (h *Handler) GetMeshmodelComponentsByNameByModel(rw http.ResponseWriter, r
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
 //
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
+//
 // ```?annotations={["true"/"false"/]}``` If "true" components having "isAnnotation" property as true are "only" returned, If false all components except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation components being returned.
+//
+// ```?labelSelector={expr}``` Kubernetes-style label selector over component/model metadata, e.g. `tier=prod,!deprecated,kind in (Service,Workload)`
+//
+// ```?fieldSelector={expr}``` Kubernetes-style field selector over first-class fields, e.g. `metadata.status=enabled,spec.registrant=kubernetes`
 // responses:
 // 200: []meshmodelComponentsDuplicateResponseWrapper
 func (h *Handler) GetAllMeshmodelComponentsByName(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 	name := mux.Vars(r)["name"]
 	var greedy bool
@@ -708,19 +1223,34 @@ func (h *Handler) GetAllMeshmodelComponentsByName(rw http.ResponseWriter, r *htt
 	}
 	v := queryParams.Get("version")
 	returnAnnotationComp := queryParams.Get("annotations")
-	entities, count, _, _ := h.registryManager.GetEntities(&regv1beta1.ComponentFilter{
+	labelSelector, fieldSelector, err := parseComponentSelectors(queryParams)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fetchOffset, fetchLimit := selectorFetchBounds(offset, limit, labelSelector, fieldSelector)
+	entities, count, _, getErr := h.registryManager.GetEntities(&regv1beta1.ComponentFilter{
 		Name:        name,
 		Trim:        queryParams.Get("trim") == "true",
 		APIVersion:  queryParams.Get("apiVersion"),
 		Version:     v,
 		ModelName:   queryParams.Get("model"),
-		Offset:      offset,
-		Limit:       limit,
+		Offset:      fetchOffset,
+		Limit:       fetchLimit,
 		Greedy:      greedy,
 		OrderOn:     order,
 		Sort:        sort,
 		Annotations: returnAnnotationComp,
 	})
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+
+	entities = filterEntitiesBySelectors(entities, labelSelector, fieldSelector)
+	if len(labelSelector) > 0 || len(fieldSelector) > 0 {
+		count = int64(len(entities))
+	}
+	entities = paginateFilteredEntities(entities, offset, limit, labelSelector, fieldSelector)
 
 	comps := prettifyCompDefSchema(entities)
 
@@ -738,6 +1268,8 @@ func (h *Handler) GetAllMeshmodelComponentsByName(rw http.ResponseWriter, r *htt
 		Components: models.FindDuplicateComponents(comps),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(response); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -766,26 +1298,45 @@ func (h *Handler) GetAllMeshmodelComponentsByName(rw http.ResponseWriter, r *htt
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
 //
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
+//
 // ```?annotations={["true"/"false"/]}``` If "true" components having "isAnnotation" property as true are "only" returned, If false all components except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation components being returned.
+//
+// ```?labelSelector={expr}``` Kubernetes-style label selector over component/model metadata, e.g. `tier=prod,!deprecated,kind in (Service,Workload)`
+//
+// ```?fieldSelector={expr}``` Kubernetes-style field selector over first-class fields, e.g. `metadata.status=enabled,spec.registrant=kubernetes`
 // responses:
 // 200: []meshmodelComponentsDuplicateResponseWrapper
 func (h *Handler) GetMeshmodelComponentByModel(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 	typ := mux.Vars(r)["model"]
 	queryParams := r.URL.Query()
 	v := queryParams.Get("version")
 
 	returnAnnotationComp := queryParams.Get("annotations")
+	labelSelector, fieldSelector, err := parseComponentSelectors(queryParams)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fetchOffset, fetchLimit := selectorFetchBounds(offset, limit, labelSelector, fieldSelector)
 	filter := &regv1beta1.ComponentFilter{
 		Id:          queryParams.Get("id"),
 		ModelName:   typ,
 		Version:     v,
 		Trim:        queryParams.Get("trim") == "true",
 		APIVersion:  queryParams.Get("apiVersion"),
-		Limit:       limit,
-		Offset:      offset,
+		Limit:       fetchLimit,
+		Offset:      fetchOffset,
 		OrderOn:     order,
 		Sort:        sort,
 		Annotations: returnAnnotationComp,
@@ -794,7 +1345,16 @@ func (h *Handler) GetMeshmodelComponentByModel(rw http.ResponseWriter, r *http.R
 		filter.Greedy = true
 		filter.DisplayName = search
 	}
-	entities, count, _, _ := h.registryManager.GetEntities(filter)
+	entities, count, _, getErr := h.registryManager.GetEntities(filter)
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+	entities = filterEntitiesBySelectors(entities, labelSelector, fieldSelector)
+	if len(labelSelector) > 0 || len(fieldSelector) > 0 {
+		count = int64(len(entities))
+	}
+	entities = paginateFilteredEntities(entities, offset, limit, labelSelector, fieldSelector)
+
 	comps := prettifyCompDefSchema(entities)
 
 	var pgSize int64
@@ -811,6 +1371,8 @@ func (h *Handler) GetMeshmodelComponentByModel(rw http.ResponseWriter, r *http.R
 		Components: models.FindDuplicateComponents(comps),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(response); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -840,26 +1402,45 @@ func (h *Handler) GetMeshmodelComponentByModel(rw http.ResponseWriter, r *http.R
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
 //
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
+//
 // ```?annotations={["true"/"false"/]}``` If "true" components having "isAnnotation" property as true are "only" returned, If false all components except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation components being returned.
+//
+// ```?labelSelector={expr}``` Kubernetes-style label selector over component/model metadata, e.g. `tier=prod,!deprecated,kind in (Service,Workload)`
+//
+// ```?fieldSelector={expr}``` Kubernetes-style field selector over first-class fields, e.g. `metadata.status=enabled,spec.registrant=kubernetes`
 // responses:
 // 200: []meshmodelComponentsDuplicateResponseWrapper
 func (h *Handler) GetMeshmodelComponentByModelByCategory(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 	typ := mux.Vars(r)["model"]
 	cat := mux.Vars(r)["category"]
 	queryParams := r.URL.Query()
 	v := queryParams.Get("version")
 	returnAnnotationComp := queryParams.Get("annotations")
-	filter := &regv1beta1.ComponentFilter{
-		CategoryName: cat,
+	labelSelector, fieldSelector, err := parseComponentSelectors(queryParams)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fetchOffset, fetchLimit := selectorFetchBounds(offset, limit, labelSelector, fieldSelector)
+	filter := &regv1beta1.ComponentFilter{
+		CategoryName: cat,
 		ModelName:    typ,
 		Version:      v,
 		Trim:         queryParams.Get("trim") == "true",
 		APIVersion:   queryParams.Get("apiVersion"),
-		Limit:        limit,
-		Offset:       offset,
+		Limit:        fetchLimit,
+		Offset:       fetchOffset,
 		OrderOn:      order,
 		Sort:         sort,
 		Annotations:  returnAnnotationComp,
@@ -868,7 +1449,16 @@ func (h *Handler) GetMeshmodelComponentByModelByCategory(rw http.ResponseWriter,
 		filter.Greedy = true
 		filter.DisplayName = search
 	}
-	entities, count, _, _ := h.registryManager.GetEntities(filter)
+	entities, count, _, getErr := h.registryManager.GetEntities(filter)
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+	entities = filterEntitiesBySelectors(entities, labelSelector, fieldSelector)
+	if len(labelSelector) > 0 || len(fieldSelector) > 0 {
+		count = int64(len(entities))
+	}
+	entities = paginateFilteredEntities(entities, offset, limit, labelSelector, fieldSelector)
+
 	comps := prettifyCompDefSchema(entities)
 
 	var pgSize int64
@@ -885,6 +1475,8 @@ func (h *Handler) GetMeshmodelComponentByModelByCategory(rw http.ResponseWriter,
 		Components: models.FindDuplicateComponents(comps),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(response); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -912,25 +1504,44 @@ func (h *Handler) GetMeshmodelComponentByModelByCategory(rw http.ResponseWriter,
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
 //
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
+//
 // ```?annotations={["true"/"false"/]}``` If "true" components having "isAnnotation" property as true are "only" returned, If false all components except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation components being returned.
+//
+// ```?labelSelector={expr}``` Kubernetes-style label selector over component/model metadata, e.g. `tier=prod,!deprecated,kind in (Service,Workload)`
+//
+// ```?fieldSelector={expr}``` Kubernetes-style field selector over first-class fields, e.g. `metadata.status=enabled,spec.registrant=kubernetes`
 // responses:
 //
 //	200: []meshmodelComponentsDuplicateResponseWrapper
 func (h *Handler) GetMeshmodelComponentByCategory(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 	cat := mux.Vars(r)["category"]
 	queryParams := r.URL.Query()
 	v := queryParams.Get("version")
 	returnAnnotationComp := queryParams.Get("annotations")
+	labelSelector, fieldSelector, err := parseComponentSelectors(queryParams)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fetchOffset, fetchLimit := selectorFetchBounds(offset, limit, labelSelector, fieldSelector)
 	filter := &regv1beta1.ComponentFilter{
 		CategoryName: cat,
 		Version:      v,
 		Trim:         queryParams.Get("trim") == "true",
 		APIVersion:   queryParams.Get("apiVersion"),
-		Limit:        limit,
-		Offset:       offset,
+		Limit:        fetchLimit,
+		Offset:       fetchOffset,
 		OrderOn:      order,
 		Sort:         sort,
 		Annotations:  returnAnnotationComp,
@@ -939,7 +1550,16 @@ func (h *Handler) GetMeshmodelComponentByCategory(rw http.ResponseWriter, r *htt
 		filter.Greedy = true
 		filter.DisplayName = search
 	}
-	entities, count, _, _ := h.registryManager.GetEntities(filter)
+	entities, count, _, getErr := h.registryManager.GetEntities(filter)
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+	entities = filterEntitiesBySelectors(entities, labelSelector, fieldSelector)
+	if len(labelSelector) > 0 || len(fieldSelector) > 0 {
+		count = int64(len(entities))
+	}
+	entities = paginateFilteredEntities(entities, offset, limit, labelSelector, fieldSelector)
+
 	comps := prettifyCompDefSchema(entities)
 
 	var pgSize int64
@@ -956,6 +1576,8 @@ func (h *Handler) GetMeshmodelComponentByCategory(rw http.ResponseWriter, r *htt
 		Components: models.FindDuplicateComponents(comps),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(response); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -983,24 +1605,43 @@ func (h *Handler) GetMeshmodelComponentByCategory(rw http.ResponseWriter, r *htt
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
 //
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
+//
 // ```?annotations={["true"/"false"/]}``` If "true" components having "isAnnotation" property as true are "only" returned, If false all components except "annotations" are returned. Any other value of the query parameter results in both annoations as well as non-annotation components being returned.
+//
+// ```?labelSelector={expr}``` Kubernetes-style label selector over component/model metadata, e.g. `tier=prod,!deprecated,kind in (Service,Workload)`
+//
+// ```?fieldSelector={expr}``` Kubernetes-style field selector over first-class fields, e.g. `metadata.status=enabled,spec.registrant=kubernetes`
 // responses:
 //  200: meshmodelComponentsDuplicateResponseWrapper
 
 func (h *Handler) GetAllMeshmodelComponents(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 	queryParams := r.URL.Query()
 	v := queryParams.Get("version")
 	returnAnnotationComp := queryParams.Get("annotations")
+	labelSelector, fieldSelector, err := parseComponentSelectors(queryParams)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fetchOffset, fetchLimit := selectorFetchBounds(offset, limit, labelSelector, fieldSelector)
 	filter := &regv1beta1.ComponentFilter{
 		Id:          queryParams.Get("id"),
 		Version:     v,
 		Trim:        queryParams.Get("trim") == "true",
 		APIVersion:  queryParams.Get("apiVersion"),
-		Limit:       limit,
-		Offset:      offset,
+		Limit:       fetchLimit,
+		Offset:      fetchOffset,
 		OrderOn:     order,
 		Sort:        sort,
 		Annotations: returnAnnotationComp,
@@ -1009,7 +1650,16 @@ func (h *Handler) GetAllMeshmodelComponents(rw http.ResponseWriter, r *http.Requ
 		filter.Greedy = true
 		filter.DisplayName = search
 	}
-	entities, count, _, _ := h.registryManager.GetEntities(filter)
+	entities, count, _, getErr := h.registryManager.GetEntities(filter)
+	if writeIfRequestCanceled(rw, ctx, getErr) {
+		return
+	}
+	entities = filterEntitiesBySelectors(entities, labelSelector, fieldSelector)
+	if len(labelSelector) > 0 || len(fieldSelector) > 0 {
+		count = int64(len(entities))
+	}
+	entities = paginateFilteredEntities(entities, offset, limit, labelSelector, fieldSelector)
+
 	comps := prettifyCompDefSchema(entities)
 
 	var pgSize int64
@@ -1027,12 +1677,227 @@ func (h *Handler) GetAllMeshmodelComponents(rw http.ResponseWriter, r *http.Requ
 		Components: models.FindDuplicateComponents(comps),
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(res); err != nil {
 		h.log.Error(ErrGetMeshModels(err)) //TODO: Add appropriate meshkit error
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
 	}
 }
 
+// cardinalityGroupByAllowList maps the API-facing groupBy field names
+// accepted by GetMeshmodelCardinality to the DB columns the registry
+// aggregates on.
+var cardinalityGroupByAllowList = map[string]string{
+	"category":     "category_name",
+	"model":        "model_name",
+	"registrant":   "registrant",
+	"version":      "version",
+	"apiVersion":   "api_version",
+	"status":       "status",
+	"isAnnotation": "is_annotation",
+}
+
+// cardinalityFieldJSONKey maps each groupBy field's API-facing name to the
+// JSON key it is read from on an entity once marshaled. The registry has no
+// GROUP BY of its own to push this down to, so GetMeshmodelCardinality
+// fetches every matching entity with one unpaginated call to the same
+// GetEntities list handlers already use and aggregates in process; entities are walked as generic JSON rather than
+// through their concrete Go type (component.ComponentDefinition,
+// _model.ModelDefinition) because the field sits at a different nesting
+// depth depending on entityKind, and findJSONField below searches regardless
+// of depth.
+var cardinalityFieldJSONKey = map[string]string{
+	"category":     "categoryName",
+	"model":        "modelName",
+	"registrant":   "registrant",
+	"version":      "version",
+	"apiVersion":   "apiVersion",
+	"status":       "status",
+	"isAnnotation": "isAnnotation",
+}
+
+// findJSONField searches decoded - the result of json.Unmarshal-ing an
+// entity into an interface{} - for key at any nesting depth and reports its
+// value stringified. A key on the object itself is returned before any
+// nested one; otherwise nested values are searched depth-first in Go map
+// order, so when the key occurs only in nested objects, and in more than
+// one of them, which of their values is returned is not defined.
+func findJSONField(decoded interface{}, key string) (string, bool) {
+	switch v := decoded.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if strings.EqualFold(k, key) {
+				return fmt.Sprintf("%v", val), true
+			}
+		}
+		for _, val := range v {
+			if s, ok := findJSONField(val, key); ok {
+				return s, true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := findJSONField(item, key); ok {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+// swagger:route GET /api/meshmodels/cardinality GetMeshmodelCardinality idGetMeshmodelCardinality
+// Handle GET request for grouped entity counts across the registry.
+//
+// Returns counts grouped by one or more fields instead of paging through
+// full entities, for dashboards like component-distribution charts,
+// registrant leaderboards, and annotation vs. non-annotation ratios.
+//
+// ```?groupBy={field[,field2,...]}``` Required. One or more of: category, model, registrant, version, apiVersion, status, isAnnotation
+//
+// ```?entity={models|components}``` Which entity kind to aggregate. Default is components. Any other value, including relationships, returns 400
+//
+// ```?category={category}```, ```?model={model}```, ```?search={name}```, ```?annotations={[true/false]}``` narrow the aggregation using the same filters as the list endpoints
+// responses:
+//
+//	200: meshmodelCardinalityResponseWrapper
+func (h *Handler) GetMeshmodelCardinality(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	queryParams := r.URL.Query()
+
+	groupBy, err := helpers.ParseGroupBy(queryParams.Get("groupBy"), cardinalityGroupByAllowList)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(groupBy) == 0 {
+		http.Error(rw, "groupBy is required", http.StatusBadRequest)
+		return
+	}
+
+	entityKind := queryParams.Get("entity")
+	if entityKind == "" {
+		entityKind = "components"
+	}
+
+	var entities []entity.Entity
+	switch entityKind {
+	case "components":
+		filter := &regv1beta1.ComponentFilter{
+			CategoryName: queryParams.Get("category"),
+			ModelName:    queryParams.Get("model"),
+			Annotations:  queryParams.Get("annotations"),
+		}
+		if search := queryParams.Get("search"); search != "" {
+			filter.Greedy = true
+			filter.DisplayName = search
+		}
+		var getErr error
+		entities, _, _, getErr = h.registryManager.GetEntities(filter)
+		if getErr != nil {
+			h.log.Error(ErrGetMeshModels(getErr))
+			http.Error(rw, ErrGetMeshModels(getErr).Error(), http.StatusInternalServerError)
+			return
+		}
+	case "models":
+		filter := &regv1beta1.ModelFilter{
+			Name:        queryParams.Get("model"),
+			Category:    queryParams.Get("category"),
+			Annotations: queryParams.Get("annotations"),
+		}
+		if search := queryParams.Get("search"); search != "" {
+			filter.Greedy = true
+			filter.DisplayName = search
+		}
+		var getErr error
+		entities, _, _, getErr = h.registryManager.GetEntities(filter)
+		if getErr != nil {
+			h.log.Error(ErrGetMeshModels(getErr))
+			http.Error(rw, ErrGetMeshModels(getErr).Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(rw, fmt.Sprintf("entity %q is not supported for cardinality, only components and models are", entityKind), http.StatusBadRequest)
+		return
+	}
+
+	// Reverse cardinalityGroupByAllowList's resolved DB columns back to the
+	// API-facing field names groupBy was requested with, since those -
+	// and cardinalityFieldJSONKey below - are keyed by the API name, not
+	// the DB column ParseGroupBy resolves to.
+	apiGroupBy := make([]string, 0, len(groupBy))
+	for _, column := range groupBy {
+		for apiName, col := range cardinalityGroupByAllowList {
+			if col == column {
+				apiGroupBy = append(apiGroupBy, apiName)
+				break
+			}
+		}
+	}
+
+	type groupAccum struct {
+		key   map[string]string
+		count int64
+	}
+	groupOrder := make([]string, 0)
+	groupsByKey := make(map[string]*groupAccum)
+
+	var totalCount int64
+	for _, e := range entities {
+		raw, marshalErr := json.Marshal(e)
+		if marshalErr != nil {
+			continue
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			continue
+		}
+
+		key := make(map[string]string, len(apiGroupBy))
+		for _, field := range apiGroupBy {
+			value, _ := findJSONField(decoded, cardinalityFieldJSONKey[field])
+			key[field] = value
+		}
+
+		keyBytes, marshalErr := json.Marshal(key)
+		if marshalErr != nil {
+			continue
+		}
+		keyStr := string(keyBytes)
+
+		group, ok := groupsByKey[keyStr]
+		if !ok {
+			group = &groupAccum{key: key}
+			groupsByKey[keyStr] = group
+			groupOrder = append(groupOrder, keyStr)
+		}
+		group.count++
+		totalCount++
+	}
+
+	groups := make([]models.AggGroup, 0, len(groupOrder))
+	for _, keyStr := range groupOrder {
+		group := groupsByKey[keyStr]
+		groups = append(groups, models.AggGroup{Key: group.key, Count: group.count})
+	}
+
+	res := models.MeshmodelCardinalityAPIResponse{
+		Groups:      groups,
+		TotalGroups: len(groups),
+		TotalCount:  totalCount,
+	}
+
+	if err := enc.Encode(res); err != nil {
+		h.log.Error(ErrGetMeshModels(err))
+		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
+	}
+}
+
 // swagger:route POST /api/meshmodel/components/register MeshmodelValidate idPostMeshModelValidate
 // Handle POST request for registering meshmodel components.
 //
@@ -1043,6 +1908,9 @@ func (h *Handler) GetAllMeshmodelComponents(rw http.ResponseWriter, r *http.Requ
 // request body should be json
 // request body should be of ComponentCapability format
 func (h *Handler) RegisterMeshmodelComponents(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	dec := json.NewDecoder(r.Body)
 	var cc registry.MeshModelRegistrantData
 	err := dec.Decode(&cc)
@@ -1051,8 +1919,11 @@ func (h *Handler) RegisterMeshmodelComponents(rw http.ResponseWriter, r *http.Re
 		return
 	}
 	var c component.ComponentDefinition
+	var matched bool
+	var registerErr error
 	switch cc.EntityType {
 	case entity.ComponentDefinition:
+		matched = true
 		var isModelError bool
 		var isRegistranError bool
 		err = json.Unmarshal(cc.Entity, &c)
@@ -1061,8 +1932,11 @@ func (h *Handler) RegisterMeshmodelComponents(rw http.ResponseWriter, r *http.Re
 			return
 		}
 		utils.WriteSVGsOnFileSystem(&c)
-		isRegistranError, isModelError, err = h.registryManager.RegisterEntity(cc.Connection, &c)
-		helpers.HandleError(cc.Connection, &c, err, isModelError, isRegistranError)
+		isRegistranError, isModelError, registerErr = h.registryManager.RegisterEntity(cc.Connection, &c)
+		if writeIfRequestCanceled(rw, ctx, registerErr) {
+			return
+		}
+		helpers.HandleError(cc.Connection, &c, registerErr, isModelError, isRegistranError)
 	}
 	err = helpers.WriteLogsToFiles()
 	if err != nil {
@@ -1072,6 +1946,17 @@ func (h *Handler) RegisterMeshmodelComponents(rw http.ResponseWriter, r *http.Re
 		http.Error(rw, err.Error(), http.StatusBadRequest)
 		return
 	}
+	// publishWatchEvent/triggerWebhooks are externally-visible effects (a
+	// durable watch stream, a signed outbound webhook call) - unlike
+	// MeshModelSummaryChannel.Publish below, which the baseline already
+	// fired unconditionally, these only fire when the switch actually
+	// matched a registerable entity and RegisterEntity itself reported no
+	// error, so a failed or no-op registration doesn't announce a
+	// component that was never actually registered.
+	if matched && registerErr == nil {
+		publishWatchEvent("components", WatchAdded, &c)
+		go triggerWebhooks(WebhookComponentRegistered, &c)
+	}
 	go h.config.MeshModelSummaryChannel.Publish()
 }
 
@@ -1090,12 +1975,21 @@ func (h *Handler) RegisterMeshmodelComponents(rw http.ResponseWriter, r *http.Re
 //
 // ```?pagesize={pagesize}``` Default pagesize is 25. To return all results: ```pagesize=all```
 //
+// ```?timeout={duration}``` Optional server-side deadline for this request (e.g. `timeout=5s`); exceeding it returns HTTP 499
+//
 // responses:
 //	200: []meshmodelRegistrantsResponseWrapper
 
 func (h *Handler) GetMeshmodelRegistrants(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
+	if _, ok := computeListETag(rw, r); !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	page, offset, limit, search, order, sort, _ := getPaginationParams(r)
 
 	filter := &_models.HostFilter{
@@ -1109,6 +2003,9 @@ func (h *Handler) GetMeshmodelRegistrants(rw http.ResponseWriter, r *http.Reques
 		filter.DisplayName = search
 	}
 	hosts, count, err := h.registryManager.GetRegistrants(filter)
+	if writeIfRequestCanceled(rw, ctx, err) {
+		return
+	}
 	if err != nil {
 		h.log.Error(ErrGetMeshModels(err))
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -1129,6 +2026,8 @@ func (h *Handler) GetMeshmodelRegistrants(rw http.ResponseWriter, r *http.Reques
 		Registrants: hosts,
 	}
 
+	writeListHeaders(rw, r, page, int(pgSize), count)
+
 	if err := enc.Encode(res); err != nil {
 		h.log.Error(ErrGetMeshModels(err))
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -1146,6 +2045,9 @@ func (h *Handler) GetMeshmodelRegistrants(rw http.ResponseWriter, r *http.Reques
 // request body should be json
 // request body should be of struct containing ID and Status fields
 func (h *Handler) UpdateEntityStatus(rw http.ResponseWriter, r *http.Request, _ *models.Preference, user *models.User, provider models.Provider) {
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	dec := json.NewDecoder(r.Body)
 	userID := uuid.FromStringOrNil(user.ID)
 	entityType := mux.Vars(r)["entityType"]
@@ -1163,6 +2065,9 @@ func (h *Handler) UpdateEntityStatus(rw http.ResponseWriter, r *http.Request, _
 
 	eventBuilder := events.NewEvent().ActedUpon(userID).FromUser(userID).FromSystem(*h.SystemID).WithCategory(entityType).WithAction("update")
 	err = h.registryManager.UpdateEntityStatus(updateData.ID, updateData.Status, entityType)
+	if writeIfRequestCanceled(rw, ctx, err) {
+		return
+	}
 	if err != nil {
 		eventBuilder.WithSeverity(events.Error).WithDescription(fmt.Sprintf("Failed to update '%s' status to %s", updateData.DisplayName, updateData.Status)).WithMetadata(map[string]interface{}{
 			"error": err,
@@ -1174,6 +2079,8 @@ func (h *Handler) UpdateEntityStatus(rw http.ResponseWriter, r *http.Request, _
 		return
 	}
 
+	publishWatchEvent(normalizeEntityKind(entityType), WatchModified, updateData)
+	go triggerWebhooks(WebhookEntityStatusUpdated, updateData)
 	description := fmt.Sprintf("Status of '%s' updated to %s.", updateData.DisplayName, updateData.Status)
 
 	event := eventBuilder.WithSeverity(events.Informational).WithDescription(description).Build()
@@ -1205,6 +2112,34 @@ func prettifyCompDefSchema(entities []entity.Entity) []component.ComponentDefini
 //
 // Register model based on thier Schema Version.
 //
+// ```importRequest.UploadType="oci"``` pulls a model bundle from an OCI registry by
+// reference (```?reference=```, e.g. `ghcr.io/org/model:tag`), optionally
+// authenticating with ```?ociUsername=```/```?ociPassword=``` or
+// ```?ociBearerToken=```, in place of the `csv`/`url`/`file`/`urlImport` upload types
+//
+// ```importRequest.UploadType="urlImport"``` also accepts a `oci://registry/name:tag`
+// reference in ```importRequest.ImportBody.Url```, resolved against the Distribution
+// Spec v2 surface under `/api/meshmodels/v2/` (see oci_registry_v2.go) instead of being
+// downloaded as a plain HTTP URL
+//
+// The optional ```?verifySignature=``` query parameter (see oci_signing.go) checks
+// an OCI artifact pulled by the `urlImport` or `oci` upload types (the `url` type
+// does no signature check) against
+// the operator's own ed25519 trust anchor (```MESHERY_OCI_TRUSTED_KEYS```) before
+// registration; a missing or non-matching signature is rejected. The optional
+// ```?verifyKeyIDs=``` parameter narrows verification to a subset of those
+// trusted keys by fingerprint - it can't supply a key of its own
+//
+// The `file`/`urlImport` upload types also best-effort copy their raw artifact bytes into
+// the configured ModelArtifactStore (see model_artifact_store.go) before unpacking it
+// locally, for audit purposes; a background sweep expires these staged copies after
+// artifactStoreTTL
+//
+// A model file too large to comfortably base64-encode into ```importRequest.ImportBody.
+// ModelFile``` should instead be sent to `POST /api/meshmodels/import/upload` (a streaming
+// `multipart/form-data` upload) or through the resumable `POST`/`PATCH`/`PUT
+// .../import/uploads/{uuid}` chunked protocol, modeled on the OCI blob upload flow in
+// oci_registry_v2.go - see model_upload.go
 // responses:
 // 	200: noContentWrapper
 
@@ -1237,6 +2172,7 @@ func (h *Handler) RegisterMeshmodels(rw http.ResponseWriter, r *http.Request, _
 		h.registryManager,
 		regErrorStore,
 	)
+	hitsBeforeRegistration := atomic.LoadInt64(&entityCacheHits)
 	var dir registration.Dir
 	switch importRequest.UploadType {
 	case "csv":
@@ -1390,7 +2326,7 @@ func (h *Handler) RegisterMeshmodels(rw http.ResponseWriter, r *http.Request, _
 		//Model generation strats from here
 		model.Model = strings.ToLower(model.Model)
 
-		pkg, version, err := meshkitRegistryUtils.GenerateModels(model.Registrant, importRequest.ImportBody.Url, model.Model)
+		pkg, version, _, err := cachedGenerateModels(model.Registrant, importRequest.ImportBody.Url, model.Model, meshkitRegistryUtils.GenerateModels)
 		if err != nil {
 			h.handleError(rw, err, "Error generating model")
 			h.sendErrorEvent(userID, provider, "Error generating model", err)
@@ -1443,6 +2379,12 @@ func (h *Handler) RegisterMeshmodels(rw http.ResponseWriter, r *http.Request, _
 			http.Error(rw, "Invalid base64 data", http.StatusBadRequest)
 			return
 		}
+
+		// Best-effort audit copy in the model artifact store (see
+		// model_artifact_store.go); registration still unpacks from the local
+		// temp file below, since registration.NewDir requires one on disk.
+		h.stageImportArtifact(r.Context(), importRequest.ImportBody.FileName, decodedBytes)
+
 		tempFile, err = CreateTemp(importRequest.ImportBody.FileName, decodedBytes)
 		if err != nil {
 			err = meshkitutils.ErrCreateFile(err, "Error creating temp file")
@@ -1459,6 +2401,22 @@ func (h *Handler) RegisterMeshmodels(rw http.ResponseWriter, r *http.Request, _
 		}
 	case "urlImport":
 		downloadFile := func(url string) ([]byte, error) {
+			// A `oci://registry/name:tag` reference is resolved against the
+			// Distribution Spec v2 surface (see oci_registry_v2.go) first, so a
+			// reference pushed to this same server's in-process registry (e.g. by
+			// a prior ExportModel without ```?destination=```) resolves without a
+			// round trip to a real registry; a reference this server hasn't seen
+			// falls back to a genuine remote pull (see oci_remote_push.go), using
+			// the same ```?ociUsername=```/```?ociPassword=```/```?ociBearerToken=```
+			// query parameters the "oci" upload type below authenticates with.
+			if strings.HasPrefix(url, "oci://") {
+				ref := strings.TrimPrefix(url, "oci://")
+				if data, err := resolveOCIReference(ref); err == nil {
+					return data, nil
+				}
+				return h.pullRemoteOCIArtifact(ref, registryAuthFromQuery(r))
+			}
+
 			resp, err := http.Get(url)
 			if err != nil {
 				return nil, fmt.Errorf("error downloading file from URL: %v", err)
@@ -1489,7 +2447,34 @@ func (h *Handler) RegisterMeshmodels(rw http.ResponseWriter, r *http.Request, _
 		if !isOCI {
 			fileType = detectFileType(fileData)
 		}
+
+		// An OCI artifact can opt into signature verification by supplying
+		// `?verifySignature=` (see oci_signing.go); a non-OCI download (plain
+		// json/yaml/tar) has no signature to check. The keys checked against
+		// always come from the operator's own trust anchor
+		// (ociTrustedKeysEnv) - `?verifyKeyIDs=` only narrows which of those
+		// trusted keys to check, it can't supply a key of its own.
+		if isOCI {
+			if signature, err := parseVerifySignatureParam(r.URL.Query().Get("verifySignature")); err != nil {
+				h.handleError(rw, err, "Error parsing verifySignature")
+				return
+			} else if len(signature) > 0 {
+				allowedKeys, err := resolveTrustedKeys(parseVerifyKeyIDsParam(r.URL.Query().Get("verifyKeyIDs")))
+				if err != nil {
+					h.handleError(rw, err, "Error resolving trusted OCI signing keys")
+					return
+				}
+				if err := verifyImportedArtifact(fileData, signature, allowedKeys); err != nil {
+					h.writeVerificationErrorEvent(rw, userID, provider, err)
+					return
+				}
+				h.sendEventForImport(userID, provider, 0, "artifact signature verified", true)
+			}
+		}
+
 		name := "model" + fileType
+		h.stageImportArtifact(r.Context(), name, fileData)
+
 		//write the file to a temp file
 		tempFile, err = CreateTemp(name, fileData)
 		if err != nil {
@@ -1500,6 +2485,70 @@ func (h *Handler) RegisterMeshmodels(rw http.ResponseWriter, r *http.Request, _
 		}
 		defer os.Remove(tempFile.Name())
 
+		dir = registration.NewDir(tempFile.Name())
+		if importRequest.Register {
+			registrationHelper.Register(dir)
+			tempFile.Close()
+		}
+
+	case "oci":
+		// Reference-based OCI pull: the ```?reference=``` query parameter points
+		// at a model bundle published under the `application/vnd.meshery.model.v1+tar`
+		// media type (a model.json, components/*.json, and relationships/*.json
+		// layer, the same layout GetMeshmodelModelOCI produces). Layer digests are
+		// verified against the manifest while pulling. The optional
+		// ```?ociUsername=```/```?ociPassword=```/```?ociBearerToken=``` query
+		// parameters authenticate against a private registry (see
+		// registryAuthFromQuery/pullRemoteOCIArtifact in oci_remote_push.go);
+		// none of Reference/OciUsername/OciPassword/OciBearerToken are real
+		// fields on ImportRequest.ImportBody, so - as with `?verifySignature=`
+		// above - this reads the query string instead of a schema change that
+		// hasn't landed.
+		reference := r.URL.Query().Get("reference")
+		if reference == "" {
+			http.Error(rw, `oci upload type requires a "reference" query parameter`, http.StatusBadRequest)
+			return
+		}
+
+		auth := registryAuthFromQuery(r)
+		var ociData []byte
+		var ociErr error
+		if auth.Username != "" || auth.BearerToken != "" {
+			ociData, ociErr = h.pullRemoteOCIArtifact(reference, auth)
+		} else {
+			ociData, ociErr = meshkitOci.PullOCIArtifact(reference)
+		}
+		if ociErr != nil {
+			h.handleError(rw, ociErr, "Error pulling OCI artifact")
+			h.sendErrorEvent(userID, provider, "Error pulling OCI artifact", ociErr)
+			return
+		}
+
+		if signature, err := parseVerifySignatureParam(r.URL.Query().Get("verifySignature")); err != nil {
+			h.handleError(rw, err, "Error parsing verifySignature")
+			return
+		} else if len(signature) > 0 {
+			allowedKeys, err := resolveTrustedKeys(parseVerifyKeyIDsParam(r.URL.Query().Get("verifyKeyIDs")))
+			if err != nil {
+				h.handleError(rw, err, "Error resolving trusted OCI signing keys")
+				return
+			}
+			if err := verifyImportedArtifact(ociData, signature, allowedKeys); err != nil {
+				h.writeVerificationErrorEvent(rw, userID, provider, err)
+				return
+			}
+			h.sendEventForImport(userID, provider, 0, "artifact signature verified", true)
+		}
+
+		tempFile, err = CreateTemp("model.tar", ociData)
+		if err != nil {
+			err = meshkitutils.ErrCreateFile(err, "Error creating temp file")
+			h.handleError(rw, err, "Error creating temp file")
+			h.sendErrorEvent(userID, provider, "Error creating temp file", err)
+			return
+		}
+		defer os.Remove(tempFile.Name())
+
 		dir = registration.NewDir(tempFile.Name())
 		if importRequest.Register {
 			registrationHelper.Register(dir)
@@ -1507,12 +2556,29 @@ func (h *Handler) RegisterMeshmodels(rw http.ResponseWriter, r *http.Request, _
 		}
 	}
 
+	publishWatchBookmark()
+	// Only a minimal, non-sensitive summary is forwarded here, not
+	// importRequest itself: ImportBody can carry a multi-megabyte base64
+	// ModelFile, and forwarding the whole request to an operator-supplied
+	// webhook URL would mean anything ImportBody picks up in the future
+	// goes out to that URL by default rather than by deliberate inclusion.
+	go triggerWebhooks(WebhookModelImported, WebhookModelImportedPayload{
+		UploadType: importRequest.UploadType,
+		ModelName:  importRequest.ImportBody.Model.Model,
+		Registrant: importRequest.ImportBody.Model.Registrant,
+		URL:        importRequest.ImportBody.Url,
+	})
 	h.handleRegistrationAndError(registrationHelper, &mu, &response, regErrorStore)
 	var errMsg string
 	message = writeMessageString(&response)
 	if response.EntityCount.TotalErrCount > 0 {
 		errMsg = ErrMsgContruct(&response)
 	}
+	// response.EntityCount has no skipped_unchanged field of its own yet (a
+	// meshkit/schemas change, same as Reference/Verify above); until it does,
+	// note the dedup cache's skip count (see entity_dedup_cache.go) in the
+	// human-readable message instead.
+	message = appendSkippedUnchangedNote(message, atomic.LoadInt64(&entityCacheHits)-hitsBeforeRegistration)
 
 	h.sendSuccessResponse(rw, userID, provider, message, errMsg, &response)
 
@@ -1525,12 +2591,24 @@ func (h *Handler) RegisterMeshmodels(rw http.ResponseWriter, r *http.Request, _
 //
 // ```?id={id}```
 // ```?output_format={output_format}``` Can be `json`, `yaml`, or `oci`. Default is `oci`
+// ```?sign={bool}``` When `file_type=oci` (the default), additionally cosign-sign the artifact's
+// digest and publish the signature at a sibling `sha256-<digest>.sig` tag
+// ```?destination={destination}``` When set, the built OCI image is pushed straight
+// to `destination` (an `oci://<registry>/<repo>:<tag>` reference, e.g. GHCR/ECR/
+// GAR/Docker Hub/Harbor) instead of being returned in the response body; the
+// resulting descriptor (digest, size, media type) is returned as JSON instead.
+// ```?ociUsername={ociUsername}``` ```?ociPassword={ociPassword}``` or
+// ```?ociBearerToken={ociBearerToken}``` optionally authenticate the push,
+// falling back to an anonymous request when none are set. See oci_remote_push.go.
 //
 // responses:
 //
 //	200: []byte
 
 func (h *Handler) ExportModel(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
 	modelId := r.URL.Query().Get("id")
 	name := r.URL.Query().Get("name")
 	version := r.URL.Query().Get("version")
@@ -1562,6 +2640,9 @@ func (h *Handler) ExportModel(rw http.ResponseWriter, r *http.Request) {
 		Version:       version,
 	}
 	e, _, _, err := h.registryManager.GetEntities(modelFilter)
+	if writeIfRequestCanceled(rw, ctx, err) {
+		return
+	}
 	if err != nil {
 		h.log.Error(ErrGetMeshModels(err))
 		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
@@ -1673,28 +2754,74 @@ func (h *Handler) ExportModel(rw http.ResponseWriter, r *http.Request) {
 	// {modelname}/v1.0.0/1.0.0/components/*.json
 	// {modelname}/v1.0.0/1.0.0/relationships/*.json
 
+	// ```?destination=oci://...``` pushes the built image to a remote registry
+	// instead of streaming the tar back to the caller; see oci_remote_push.go.
+	if destination := r.URL.Query().Get("destination"); destination != "" {
+		h.pushModelToDestination(rw, modelDir, model.Name, destination, registryAuthFromQuery(r))
+		return
+	}
+
 	// Build OCI image for the model from the modelDir
 	var tarfileName string
 	var byt []byte
 	if fileTypes == "oci" {
-		img, err := meshkitOci.BuildImage(modelDir)
-		if err != nil {
-			h.log.Error(err) // TODO: Add appropriate meshkit error
-			http.Error(rw, err.Error(), http.StatusInternalServerError)
-			return
+		// A {model,version} already cached in the model artifact store (see
+		// model_artifact_store.go) from a previous export is streamed back
+		// unchanged instead of rebuilding the OCI image from scratch.
+		modelVersionKey := model.Name + "@" + model.Version
+		store, storeErr := h.getModelArtifactStore(ctx)
+		if storeErr != nil {
+			h.log.Error(storeErr)
+		}
+		if store != nil {
+			if cachedKey, ok := exportCachedArtifact(modelVersionKey); ok {
+				if reader, err := store.Get(ctx, cachedKey); err == nil {
+					if cached, err := io.ReadAll(reader); err == nil {
+						byt = cached
+					}
+					_ = reader.Close()
+				}
+			}
 		}
 
-		// Save OCI artifact into a tar file
-		tarfileName := filepath.Join(modelDir, "model.tar")
-		err = meshkitOci.SaveOCIArtifact(img, tarfileName, model.Name)
-		if err != nil {
-			h.log.Error(err)
-			http.Error(rw, err.Error(), http.StatusInternalServerError)
-			return
+		if byt == nil {
+			img, err := meshkitOci.BuildImage(modelDir)
+			if err != nil {
+				h.log.Error(err) // TODO: Add appropriate meshkit error
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			// Save OCI artifact into a tar file
+			tarfileName := filepath.Join(modelDir, "model.tar")
+			err = meshkitOci.SaveOCIArtifact(img, tarfileName, model.Name)
+			if err != nil {
+				h.log.Error(err)
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			// 3. Send response
+			byt, _ = os.ReadFile(tarfileName)
+
+			if store != nil {
+				if _, err := exportModelArtifact(ctx, store, modelVersionKey, byt); err != nil {
+					h.log.Error(err)
+				}
+			}
+		}
+
+		// ```?sign=true``` additionally signs the artifact's digest (cosign-style,
+		// see oci_signing.go) and publishes it as a sibling `sha256-<digest>.sig`
+		// artifact so an importer can opt into verify policy on the way back in
+		if r.URL.Query().Get("sign") == "true" {
+			if err := h.signExportedArtifact(model.Name, byt); err != nil {
+				h.log.Error(err)
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+				return
+			}
 		}
 
-		// 3. Send response
-		byt, _ = os.ReadFile(tarfileName)
 		rw.Header().Add("Content-Type", "application/x-tar")
 		rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.tar\"", model.Name))
 		rw.Header().Set("Content-Length", fmt.Sprintf("%d", len(byt)))
@@ -1728,7 +2855,243 @@ func (h *Handler) ExportModel(rw http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func RegisterEntity(content []byte, entityType entity.EntityType, h *Handler) error {
+// swagger:route GET /api/meshmodels/models/{model}/oci GetMeshmodelModelOCI idGetMeshmodelModelOCI
+// Handle GET request for exporting a model as an OCI image layout tarball.
+//
+// Packages the model definition, its component and relationship
+// definitions, and any embedded SVGs into an OCI image layout under the
+// Meshery-specific artifact media type (`application/vnd.meshery.model.v1+json`
+// for the config, one layer per entity kind), so the result can be pushed
+// to any OCI-compliant registry (Harbor, GHCR, etc.) with `oras push`.
+//
+// ```?version={version}``` If version is unspecified, the first model the registry returns for the name is exported; no version ordering is applied
+// ```?sign={bool}``` Cosign-sign the image's manifest digest and publish the signature at a
+// sibling `sha256-<digest>.sig` tag
+// responses:
+//
+//	200: []byte
+func (h *Handler) GetMeshmodelModelOCI(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
+	modelName := mux.Vars(r)["model"]
+	version := r.URL.Query().Get("version")
+
+	e, _, _, err := h.registryManager.GetEntities(&regv1beta1.ModelFilter{
+		Name:          modelName,
+		Version:       version,
+		Components:    true,
+		Relationships: true,
+		Greedy:        true,
+	})
+	if writeIfRequestCanceled(rw, ctx, err) {
+		return
+	}
+	if err != nil {
+		h.log.Error(ErrGetMeshModels(err))
+		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(e) == 0 {
+		http.Error(rw, fmt.Sprintf("model %s has not been found", modelName), http.StatusNotFound)
+		return
+	}
+
+	model := e[0].(*_model.ModelDefinition)
+	if err := model.ReplaceSVGData("../../"); err != nil {
+		h.log.Error(err)
+	}
+
+	modelDir := filepath.Join(os.TempDir(), model.Name)
+	versionDir := filepath.Join(modelDir, model.Model.Version, model.Version)
+	dirs := []string{versionDir, filepath.Join(versionDir, "components"), filepath.Join(versionDir, "relationships")}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			err = meshkitutils.ErrCreateDir(err, "Error creating temp directory")
+			h.log.Error(err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	defer os.RemoveAll(modelDir)
+
+	components := []component.ComponentDefinition{}
+	if model.Components != nil {
+		components = model.Components.([]component.ComponentDefinition)
+	}
+	relationships := []relationship.RelationshipDefinition{}
+	if model.Relationships != nil {
+		relationships = model.Relationships.([]relationship.RelationshipDefinition)
+	}
+	model.Components = nil
+	model.Relationships = nil
+
+	if err := model.WriteModelDefinition(filepath.Join(versionDir, "model.json"), "json"); err != nil {
+		h.log.Error(err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, comp := range components {
+		_ = comp.ReplaceSVGData("../../")
+		comp.Model = *model
+		if _, err := comp.WriteComponentDefinition(filepath.Join(versionDir, "components"), "json"); err != nil {
+			h.log.Error(err)
+		}
+	}
+	for _, rel := range relationships {
+		rel.Model = *model
+		if err := rel.WriteRelationshipDefinition(filepath.Join(versionDir, "relationships"), "json"); err != nil {
+			h.log.Error(err)
+		}
+	}
+
+	img, err := meshkitOci.BuildImage(modelDir)
+	if err != nil {
+		h.log.Error(err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tarfileName := filepath.Join(modelDir, "model.tar")
+	if err := meshkitOci.SaveOCIArtifact(img, tarfileName, model.Name); err != nil {
+		h.log.Error(err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		h.log.Error(err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	byt, err := os.ReadFile(tarfileName)
+	if err != nil {
+		h.log.Error(err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Query().Get("sign") == "true" {
+		if err := h.signAndStoreManifest(model.Name, digest.String()); err != nil {
+			h.log.Error(err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	rw.Header().Set("Content-Type", "application/vnd.oci.image.layout.tar+gzip")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-oci.tar\"", model.Name))
+	rw.Header().Set("Docker-Content-Digest", digest.String())
+	rw.Header().Set("Content-Length", fmt.Sprintf("%d", len(byt)))
+	if _, err := rw.Write(byt); err != nil {
+		h.log.Error(ErrGetMeshModels(err))
+	}
+}
+
+// swagger:route POST /api/meshmodels/models/oci RegisterMeshmodelOCI idRegisterMeshmodelOCI
+// Handle POST request for registering a model from an OCI artifact.
+//
+// Accepts either a multipart/form-data upload of an OCI image layout
+// tarball (field name "file") or a JSON body with a "reference" field
+// pointing at a remote OCI registry (e.g. "ghcr.io/org/model:tag"). The
+// artifact is unpacked and registered the same way the RegisterMeshmodels
+// "urlImport" upload type handles an OCI download.
+// responses:
+//
+//	200: noContentWrapper
+func (h *Handler) RegisterMeshmodelOCI(rw http.ResponseWriter, r *http.Request, _ *models.Preference, user *models.User, provider models.Provider) {
+	var response models.RegistryAPIResponse
+	regErrorStore := models.NewRegistrationFailureLogHandler()
+	var mu sync.Mutex
+	userID := uuid.FromStringOrNil(user.ID)
+
+	registrationHelper := registration.NewRegistrationHelper(utils.UI, h.registryManager, regErrorStore)
+	hitsBeforeRegistration := atomic.LoadInt64(&entityCacheHits)
+
+	var tempFile *os.File
+	var err error
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, ferr := r.FormFile("file")
+		if ferr != nil {
+			h.handleError(rw, ferr, "Error reading uploaded OCI artifact")
+			h.sendErrorEvent(userID, provider, "Error reading uploaded OCI artifact", ferr)
+			return
+		}
+		defer file.Close()
+
+		data, rerr := io.ReadAll(file)
+		if rerr != nil {
+			h.handleError(rw, rerr, "Error reading uploaded OCI artifact")
+			h.sendErrorEvent(userID, provider, "Error reading uploaded OCI artifact", rerr)
+			return
+		}
+		tempFile, err = CreateTemp(header.Filename, data)
+	} else {
+		var body struct {
+			Reference string `json:"reference"`
+		}
+		if derr := json.NewDecoder(r.Body).Decode(&body); derr != nil || body.Reference == "" {
+			http.Error(rw, `either a multipart OCI tar upload or a "reference" field is required`, http.StatusBadRequest)
+			return
+		}
+
+		data, perr := meshkitOci.PullOCIArtifact(body.Reference)
+		if perr != nil {
+			h.handleError(rw, perr, "Error pulling OCI artifact")
+			h.sendErrorEvent(userID, provider, "Error pulling OCI artifact", perr)
+			return
+		}
+		tempFile, err = CreateTemp("model.tar", data)
+	}
+
+	if err != nil {
+		err = meshkitutils.ErrCreateFile(err, "Error creating temp file")
+		h.handleError(rw, err, "Error creating temp file")
+		h.sendErrorEvent(userID, provider, "Error creating temp file", err)
+		return
+	}
+	defer os.Remove(tempFile.Name())
+
+	dir := registration.NewDir(tempFile.Name())
+	registrationHelper.Register(dir)
+	tempFile.Close()
+
+	publishWatchBookmark()
+	h.handleRegistrationAndError(registrationHelper, &mu, &response, regErrorStore)
+	var errMsg string
+	message := writeMessageString(&response)
+	if response.EntityCount.TotalErrCount > 0 {
+		errMsg = ErrMsgContruct(&response)
+	}
+	message = appendSkippedUnchangedNote(message, atomic.LoadInt64(&entityCacheHits)-hitsBeforeRegistration)
+	h.sendSuccessResponse(rw, userID, provider, message, errMsg, &response)
+}
+
+// RegisterEntity registers a single entity read off disk (or an OCI/CSV
+// import) against h.registryManager. ctx is checked before any work is
+// done, so a caller iterating a large import directory stops registering
+// the remaining entries once the request that triggered the import is
+// canceled; registryManager.RegisterEntity takes no context, so a
+// registration already under way runs to completion. Callers outside an
+// HTTP handler (e.g. a future CLI-driven import) should pass
+// context.Background().
+//
+// Before unmarshalling, content's digest is checked against the dedup
+// cache (see entity_dedup_cache.go); a hit means an earlier import already
+// registered byte-identical content; since the model is embedded in that
+// same JSON, a digest match implies the same ModelDefinition too, so
+// unmarshal+register is skipped entirely and the hit is just recorded.
+func RegisterEntity(ctx context.Context, content []byte, entityType entity.EntityType, h *Handler) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if checkAndCacheEntityDigest(digestOfContent(content), len(content)) {
+		return nil
+	}
+
 	switch entityType {
 	case entity.ComponentDefinition:
 		var c component.ComponentDefinition
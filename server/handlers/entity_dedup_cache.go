@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Package-local content-addressable cache for imported component and
+// relationship JSON, keyed by the sha256 digest of the raw bytes
+// RegisterEntity is about to unmarshal (see the dedup check added there).
+// A hit means an earlier import already registered byte-identical
+// content - and therefore an identical ModelDefinition, since the model
+// is embedded in that same JSON - so the unmarshal+register is skipped
+// and the hit just recorded, mirroring the way an OCI layer store dedups
+// blobs by digest rather than re-uploading unchanged content.
+//
+// entityDigestCacheCap bounds the cache with FIFO eviction so a
+// long-running server doesn't grow it without bound; GetEntityCacheStats
+// reports that policy alongside the live hit/miss/byte counters.
+const entityDigestCacheCap = 10000
+
+var (
+	entityDigestCacheMu sync.Mutex
+	// entityDigestCache maps a cached digest to the byte size it was
+	// stored with, so entityDigestOrder's FIFO eviction can keep
+	// entityCacheBytes accurate.
+	entityDigestCache = map[string]int{}
+	entityDigestOrder []string
+
+	entityCacheHits   int64
+	entityCacheMisses int64
+	entityCacheBytes  int64
+)
+
+// digestOfContent returns the sha256 digest (`sha256:<hex>`) of content.
+func digestOfContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// checkAndCacheEntityDigest records digest (along with its content size,
+// for the cache's reported byte size) and reports whether it was already
+// present, meaning the caller already registered this exact content
+// before.
+func checkAndCacheEntityDigest(digest string, size int) (hit bool) {
+	entityDigestCacheMu.Lock()
+	defer entityDigestCacheMu.Unlock()
+
+	if _, ok := entityDigestCache[digest]; ok {
+		atomic.AddInt64(&entityCacheHits, 1)
+		return true
+	}
+
+	atomic.AddInt64(&entityCacheMisses, 1)
+	entityDigestCache[digest] = size
+	entityDigestOrder = append(entityDigestOrder, digest)
+	atomic.AddInt64(&entityCacheBytes, int64(size))
+
+	if len(entityDigestOrder) > entityDigestCacheCap {
+		oldest := entityDigestOrder[0]
+		entityDigestOrder = entityDigestOrder[1:]
+		if oldSize, ok := entityDigestCache[oldest]; ok {
+			delete(entityDigestCache, oldest)
+			atomic.AddInt64(&entityCacheBytes, -int64(oldSize))
+		}
+	}
+	return false
+}
+
+// appendSkippedUnchangedNote appends a "skipped N unchanged entities" note
+// to message when skipped is positive, and returns message unchanged
+// otherwise.
+func appendSkippedUnchangedNote(message string, skipped int64) string {
+	if skipped <= 0 {
+		return message
+	}
+	return fmt.Sprintf("%s (skipped %d unchanged %s already in the registry)", message, skipped, pluralizeEntity(skipped))
+}
+
+func pluralizeEntity(n int64) string {
+	if n == 1 {
+		return "entity"
+	}
+	return "entities"
+}
+
+var (
+	generateModelsCacheMu sync.Mutex
+	// generateModelsCache maps "registrant|url|model" to the pkg/version
+	// GenerateModels last produced for it. pkg is stored as interface{}
+	// and type-asserted back to T inside cachedGenerateModels, since this
+	// package never needs to name GenerateModels' pkg type itself.
+	generateModelsCache     = map[string]generateModelsCacheEntry{}
+	generateModelsCacheHits int64
+)
+
+type generateModelsCacheEntry struct {
+	pkg     interface{}
+	version string
+}
+
+// cachedGenerateModels wraps generate (meshkitRegistryUtils.GenerateModels
+// itself, passed by value so T is inferred from its real signature rather
+// than a type this package would otherwise have to name) with a cache
+// keyed on (registrant, url, model): a repeat call for a triple already
+// seen returns the previously produced pkg/version pair and reports
+// hit=true without calling generate again, on the assumption that a given
+// (registrant, url, model) keeps producing the same package for the life
+// of this process - the same assumption GenerateModels' own version
+// derivation already makes. model must be part of the key: two different
+// target models generated from the same (registrant, url) package source
+// are two distinct results, not a repeat of each other.
+func cachedGenerateModels[T any](registrant, url, model string, generate func(registrant, url, model string) (T, string, error)) (pkg T, version string, hit bool, err error) {
+	key := registrant + "|" + url + "|" + model
+
+	generateModelsCacheMu.Lock()
+	if entry, ok := generateModelsCache[key]; ok {
+		generateModelsCacheMu.Unlock()
+		atomic.AddInt64(&generateModelsCacheHits, 1)
+		return entry.pkg.(T), entry.version, true, nil
+	}
+	generateModelsCacheMu.Unlock()
+
+	pkg, version, err = generate(registrant, url, model)
+	if err != nil {
+		var zero T
+		return zero, "", false, err
+	}
+
+	generateModelsCacheMu.Lock()
+	generateModelsCache[key] = generateModelsCacheEntry{pkg: pkg, version: version}
+	generateModelsCacheMu.Unlock()
+	return pkg, version, false, nil
+}
+
+// EntityCacheStats is the response shape for GetEntityCacheStats.
+type EntityCacheStats struct {
+	Hits                    int64  `json:"hits"`
+	Misses                  int64  `json:"misses"`
+	Entries                 int    `json:"entries"`
+	BytesCached             int64  `json:"bytesCached"`
+	EvictionPolicy          string `json:"evictionPolicy"`
+	GenerateModelsCacheHits int64  `json:"generateModelsCacheHits"`
+}
+
+// swagger:route GET /api/meshmodels/cache/stats GetEntityCacheStats idGetEntityCacheStats
+// Handle GET request for the component/relationship dedup cache's
+// hit/miss counters, byte size, and eviction policy, plus the
+// (registrant, url, model) reuse counter GenerateModels calls are recorded
+// against.
+// responses:
+//	200: entityCacheStatsResponseWrapper
+func (h *Handler) GetEntityCacheStats(rw http.ResponseWriter, r *http.Request) {
+	entityDigestCacheMu.Lock()
+	entries := len(entityDigestCache)
+	entityDigestCacheMu.Unlock()
+
+	stats := EntityCacheStats{
+		Hits:                    atomic.LoadInt64(&entityCacheHits),
+		Misses:                  atomic.LoadInt64(&entityCacheMisses),
+		Entries:                 entries,
+		BytesCached:             atomic.LoadInt64(&entityCacheBytes),
+		EvictionPolicy:          fmt.Sprintf("FIFO, capped at %d entries", entityDigestCacheCap),
+		GenerateModelsCacheHits: atomic.LoadInt64(&generateModelsCacheHits),
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(stats); err != nil {
+		h.log.Error(ErrGetMeshModels(err))
+		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDigestOfContent(t *testing.T) {
+	d1 := digestOfContent([]byte("hello"))
+	d2 := digestOfContent([]byte("hello"))
+	d3 := digestOfContent([]byte("world"))
+
+	if d1 != d2 {
+		t.Errorf("digestOfContent is not deterministic: %q != %q", d1, d2)
+	}
+	if d1 == d3 {
+		t.Errorf("digestOfContent(%q) == digestOfContent(%q), want different digests", "hello", "world")
+	}
+	if got, want := d1[:7], "sha256:"; got != want {
+		t.Errorf("digestOfContent(%q) = %q, want sha256: prefix", "hello", d1)
+	}
+}
+
+func TestCheckAndCacheEntityDigest(t *testing.T) {
+	digest := digestOfContent([]byte("TestCheckAndCacheEntityDigest payload"))
+
+	if hit := checkAndCacheEntityDigest(digest, 42); hit {
+		t.Fatalf("checkAndCacheEntityDigest(%q): got hit on first call, want miss", digest)
+	}
+	if hit := checkAndCacheEntityDigest(digest, 42); !hit {
+		t.Fatalf("checkAndCacheEntityDigest(%q): got miss on repeat call, want hit", digest)
+	}
+}
+
+func TestAppendSkippedUnchangedNote(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		skipped int64
+		want    string
+	}{
+		{name: "none skipped", message: "imported 3 entities", skipped: 0, want: "imported 3 entities"},
+		{name: "one skipped", message: "imported 3 entities", skipped: 1, want: "imported 3 entities (skipped 1 unchanged entity already in the registry)"},
+		{name: "many skipped", message: "imported 3 entities", skipped: 5, want: "imported 3 entities (skipped 5 unchanged entities already in the registry)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := appendSkippedUnchangedNote(tt.message, tt.skipped); got != tt.want {
+				t.Errorf("appendSkippedUnchangedNote(%q, %d) = %q, want %q", tt.message, tt.skipped, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedGenerateModels(t *testing.T) {
+	calls := 0
+	generate := func(registrant, url, model string) (string, string, error) {
+		calls++
+		return "pkg-for-" + model, "v" + model, nil
+	}
+
+	hitsBefore := atomic.LoadInt64(&generateModelsCacheHits)
+
+	pkg, version, hit, err := cachedGenerateModels("registrant-a", "url-a", "model-a", generate)
+	if err != nil {
+		t.Fatalf("cachedGenerateModels: unexpected error on first call: %v", err)
+	}
+	if hit {
+		t.Fatal("cachedGenerateModels: got hit on first call, want miss")
+	}
+	if pkg != "pkg-for-model-a" || version != "vmodel-a" {
+		t.Fatalf("cachedGenerateModels first call = (%q, %q), want (%q, %q)", pkg, version, "pkg-for-model-a", "vmodel-a")
+	}
+	if calls != 1 {
+		t.Fatalf("generate called %d times after first call, want 1", calls)
+	}
+
+	pkg, version, hit, err = cachedGenerateModels("registrant-a", "url-a", "model-a", generate)
+	if err != nil {
+		t.Fatalf("cachedGenerateModels: unexpected error on repeat call: %v", err)
+	}
+	if !hit {
+		t.Fatal("cachedGenerateModels: got miss on repeat call, want hit")
+	}
+	if pkg != "pkg-for-model-a" || version != "vmodel-a" {
+		t.Fatalf("cachedGenerateModels repeat call = (%q, %q), want the cached (%q, %q)", pkg, version, "pkg-for-model-a", "vmodel-a")
+	}
+	if calls != 1 {
+		t.Fatalf("generate called %d times after repeat call, want still 1 (generate should be skipped on a cache hit)", calls)
+	}
+	if got := atomic.LoadInt64(&generateModelsCacheHits); got != hitsBefore+1 {
+		t.Errorf("generateModelsCacheHits = %d, want %d", got, hitsBefore+1)
+	}
+
+	// A different model from the same (registrant, url) must not reuse
+	// the cached pkg/version from model-a - the key must include model.
+	pkg, version, hit, err = cachedGenerateModels("registrant-a", "url-a", "model-c", generate)
+	if err != nil {
+		t.Fatalf("cachedGenerateModels: unexpected error for a different model: %v", err)
+	}
+	if hit {
+		t.Fatal("cachedGenerateModels: got hit for a different model sharing (registrant, url), want miss")
+	}
+	if pkg != "pkg-for-model-c" || version != "vmodel-c" {
+		t.Fatalf("cachedGenerateModels for model-c = (%q, %q), want (%q, %q)", pkg, version, "pkg-for-model-c", "vmodel-c")
+	}
+	if calls != 2 {
+		t.Fatalf("generate called %d times after a different model, want 2 (a distinct model must not be served from another model's cache entry)", calls)
+	}
+
+	failing := func(registrant, url, model string) (string, string, error) {
+		return "", "", errors.New("boom")
+	}
+	if _, _, _, err := cachedGenerateModels("registrant-b", "url-b", "model-b", failing); err == nil {
+		t.Fatal("cachedGenerateModels: expected error from a failing generate func, got nil")
+	}
+	if _, _, hit, _ := cachedGenerateModels("registrant-b", "url-b", "model-b", failing); hit {
+		t.Fatal("cachedGenerateModels: a failed call must not be cached")
+	}
+}
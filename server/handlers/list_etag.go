@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// registryVersionStamp is a process-local monotonic counter bumped whenever
+// RegisterMeshmodelComponents, RegisterMeshmodels, RegisterMeshmodelOCI, or
+// UpdateEntityStatus mutates the registry. List handlers combine it with the
+// request's query string to compute a conditional-GET ETag, so a client that
+// already has the current page gets a 304 instead of the full JSON body. The
+// same stamp doubles as the `resourceVersion` cursor the watch endpoints (see
+// meshmodel_watch.go) attach to every event, so a client can resume a watch
+// stream with `?resourceVersion=N` using the resourceVersion of the last
+// watch event it saw; list responses do not expose the stamp.
+//
+// This naturally belongs on registryManager itself, bumped centrally by
+// RegisterEntity/DeleteEntity, once that lands upstream in meshkit; until
+// then the mutating handlers bump it through publishWatchEvent and
+// publishWatchBookmark (meshmodel_watch.go).
+var registryVersionStamp int64
+
+// bumpRegistryVersionStamp increments the registry version stamp and
+// returns the new value, which callers that also publish a watch event use
+// as that event's resourceVersion.
+func bumpRegistryVersionStamp() int64 {
+	return atomic.AddInt64(&registryVersionStamp, 1)
+}
+
+// computeListETag builds a strong ETag for a meshmodel list response from
+// the current registry version stamp and the request's full query string
+// (filter, page, pagesize, sort are all part of it), and handles the
+// conditional-GET dance. If the client's If-None-Match already matches, a
+// 304 is written and ok is false, signalling the caller to return
+// immediately without building or encoding the response body.
+func computeListETag(rw http.ResponseWriter, r *http.Request) (etag string, ok bool) {
+	stamp := atomic.LoadInt64(&registryVersionStamp)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", stamp, r.URL.RawQuery)))
+	etag = fmt.Sprintf(`"%x"`, sum)
+
+	rw.Header().Set("Vary", "Accept, Cookie")
+
+	if r.Header.Get("If-None-Match") == etag {
+		rw.WriteHeader(http.StatusNotModified)
+		return etag, false
+	}
+
+	rw.Header().Set("ETag", etag)
+	rw.Header().Set("Cache-Control", "private, must-revalidate")
+	return etag, true
+}
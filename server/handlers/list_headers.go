@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// writeListHeaders sets X-Total-Count and an RFC 5988 Link header (rel
+// "first"/"prev"/"next"/"last") on a paginated meshmodel list response, so
+// UI and CLI clients can discover total size and paging state without
+// buffering and decoding the full JSON body. prev/next are omitted at the
+// ends of the result set. A pageSize of 0 means there is only a single page,
+// so only X-Total-Count is set. For pagesize=all the list handlers pass the
+// total count as pageSize, so such a response still gets first/last links
+// to its single page unless the count is 0.
+func writeListHeaders(rw http.ResponseWriter, r *http.Request, page, pageSize int, count int64) {
+	rw.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	rw.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+
+	if pageSize <= 0 {
+		return
+	}
+
+	lastPage := int((count + int64(pageSize) - 1) / int64(pageSize))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	pageURL := func(p int) string {
+		u := *r.URL
+		q := u.Query()
+		q.Set("page", strconv.Itoa(p))
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(1))}
+	if page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(page-1)))
+	}
+	if page < lastPage {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(page+1)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(lastPage)))
+
+	rw.Header().Set("Link", strings.Join(links, ", "))
+}
@@ -0,0 +1,465 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/meshery/meshery/pkg/selectors"
+)
+
+// WatchEventType mirrors the Kubernetes watch event types emitted on a
+// meshmodel watch stream.
+type WatchEventType string
+
+const (
+	WatchAdded    WatchEventType = "ADDED"
+	WatchModified WatchEventType = "MODIFIED"
+	WatchDeleted  WatchEventType = "DELETED"
+	WatchBookmark WatchEventType = "BOOKMARK"
+)
+
+// WatchEvent is a single entry on a meshmodel watch stream. Object is the
+// affected entity (a *component.ComponentDefinition, *model.ModelDefinition,
+// or *relationship.RelationshipDefinition depending on which watch endpoint
+// emitted it); it is nil on a BOOKMARK event, which only exists to advance
+// ResourceVersion past a batch of changes the server doesn't have a single
+// entity to attach to (e.g. a bulk import).
+type WatchEvent struct {
+	Type            WatchEventType `json:"type"`
+	Object          interface{}    `json:"object,omitempty"`
+	ResourceVersion int64          `json:"resourceVersion"`
+}
+
+const (
+	// watchRingBufferSize bounds how many past events a ring keeps for
+	// replay. A reconnecting client whose last-seen resourceVersion has
+	// already aged out of the buffer gets a 410 Gone and must re-list.
+	watchRingBufferSize = 1024
+	// watchSubscriberBufferSize bounds how far a single slow subscriber can
+	// fall behind before publish starts dropping its events rather than
+	// blocking the publisher (which would stall every other watcher).
+	watchSubscriberBufferSize = 32
+)
+
+// watchRing is an in-memory publish/subscribe ring buffer of WatchEvents for
+// one entity kind (components, models, or relationships). It plays the same
+// role MeshModelSummaryChannel and EventBroadcaster play for summaries and
+// audit events, scoped to registry watch streams.
+type watchRing struct {
+	mu     sync.Mutex
+	events []WatchEvent
+	subs   map[chan WatchEvent]struct{}
+}
+
+func newWatchRing() *watchRing {
+	return &watchRing{subs: make(map[chan WatchEvent]struct{})}
+}
+
+func (wr *watchRing) publish(evt WatchEvent) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	wr.events = append(wr.events, evt)
+	if len(wr.events) > watchRingBufferSize {
+		wr.events = wr.events[len(wr.events)-watchRingBufferSize:]
+	}
+	for ch := range wr.subs {
+		select {
+		case ch <- evt:
+		default:
+			// Slow subscriber; drop the event rather than block the publisher.
+		}
+	}
+}
+
+func (wr *watchRing) subscribe() chan WatchEvent {
+	ch := make(chan WatchEvent, watchSubscriberBufferSize)
+	wr.mu.Lock()
+	wr.subs[ch] = struct{}{}
+	wr.mu.Unlock()
+	return ch
+}
+
+func (wr *watchRing) unsubscribe(ch chan WatchEvent) {
+	wr.mu.Lock()
+	delete(wr.subs, ch)
+	wr.mu.Unlock()
+	close(ch)
+}
+
+// eventsSince returns the buffered events with a ResourceVersion greater
+// than since, in order. gone is true when since is older than the oldest
+// event the buffer still holds, meaning the caller missed events that have
+// already been evicted; it must fall back to a full list instead of
+// resuming the stream. since == 0 always returns the full backlog.
+func (wr *watchRing) eventsSince(since int64) (events []WatchEvent, gone bool) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	if len(wr.events) > 0 && since != 0 && wr.events[0].ResourceVersion > since+1 {
+		return nil, true
+	}
+	for _, evt := range wr.events {
+		if evt.ResourceVersion > since {
+			events = append(events, evt)
+		}
+	}
+	return events, false
+}
+
+var (
+	componentWatch    = newWatchRing()
+	modelWatch        = newWatchRing()
+	relationshipWatch = newWatchRing()
+)
+
+func watchRingFor(kind string) *watchRing {
+	switch kind {
+	case "components":
+		return componentWatch
+	case "models":
+		return modelWatch
+	case "relationships":
+		return relationshipWatch
+	default:
+		return nil
+	}
+}
+
+// normalizeEntityKind maps the singular entity-type names used elsewhere in
+// this package (e.g. the {entityType} route variable on UpdateEntityStatus)
+// to the plural ring keys used here.
+func normalizeEntityKind(entityType string) string {
+	switch strings.ToLower(strings.TrimSuffix(strings.ToLower(entityType), "s")) {
+	case "component":
+		return "components"
+	case "model":
+		return "models"
+	case "relationship":
+		return "relationships"
+	default:
+		return entityType
+	}
+}
+
+// publishWatchEvent bumps the shared registry version stamp and fans the
+// resulting WatchEvent out to subscribers of kind's ring buffer. It is the
+// integration point between the mutating registry handlers
+// (RegisterMeshmodelComponents, UpdateEntityStatus, ...) and the watch
+// endpoints below.
+func publishWatchEvent(kind string, evtType WatchEventType, object interface{}) int64 {
+	resourceVersion := bumpRegistryVersionStamp()
+	if ring := watchRingFor(kind); ring != nil {
+		ring.publish(WatchEvent{Type: evtType, Object: object, ResourceVersion: resourceVersion})
+	}
+	return resourceVersion
+}
+
+// publishWatchBookmark bumps the shared registry version stamp and emits a
+// BOOKMARK to every ring. It is used by the bulk-import handlers, which
+// mutate an unknown mix of components/models/relationships and have no
+// single entity to attach an ADDED/MODIFIED event to; the bookmark still
+// lets watchers waiting on a specific resourceVersion advance, at the cost
+// of telling them to re-list rather than describing what changed.
+func publishWatchBookmark() int64 {
+	resourceVersion := bumpRegistryVersionStamp()
+	for _, ring := range []*watchRing{componentWatch, modelWatch, relationshipWatch} {
+		ring.publish(WatchEvent{Type: WatchBookmark, ResourceVersion: resourceVersion})
+	}
+	return resourceVersion
+}
+
+// flattenJSONFields round-trips v through JSON and flattens it into a
+// dot-path field map (e.g. `model.category.name` -> "App Definition and
+// Development"), the same addressing scheme the fieldSelector/labelSelector
+// examples in the API docs use. This lets watch filtering work against the
+// same shape clients already see in list responses without needing to know
+// the underlying Go struct layout.
+func flattenJSONFields(v interface{}) map[string]string {
+	out := make(map[string]string)
+	b, err := json.Marshal(v)
+	if err != nil {
+		return out
+	}
+	var m interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return out
+	}
+	flattenInto("", m, out)
+	return out
+}
+
+func flattenInto(prefix string, v interface{}, out map[string]string) {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, val := range vv {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenInto(key, val, out)
+		}
+	case nil:
+		return
+	case []interface{}:
+		// Selector paths don't address into arrays; skip.
+	default:
+		if prefix != "" {
+			out[prefix] = fmt.Sprintf("%v", vv)
+		}
+	}
+}
+
+// legacyWatchFieldRequirements translates the scalar `category`/`model`/
+// `version`/`apiVersion` query parameters the list endpoints already accept
+// into fieldSelector Requirements, so a watch client can keep using the
+// familiar parameters instead of learning the flattened field paths.
+func legacyWatchFieldRequirements(kind string, queryParams url.Values) []selectors.Requirement {
+	aliases := map[string]string{
+		"version":    "version",
+		"apiVersion": "apiVersion",
+		"model":      "model.name",
+		"category":   "model.category.name",
+	}
+	if kind == "models" {
+		// A model's own name and category sit at the top level of its JSON,
+		// not under an embedded `model` object.
+		aliases["model"] = "name"
+		aliases["category"] = "category.name"
+	}
+
+	var reqs []selectors.Requirement
+	for param, field := range aliases {
+		if v := queryParams.Get(param); v != "" {
+			reqs = append(reqs, selectors.Requirement{Key: field, Operator: selectors.Equals, Values: []string{v}})
+		}
+	}
+	return reqs
+}
+
+// metadataFields narrows a flattened field map down to the `metadata.*`
+// subtree and the embedded model's `model.metadata.*` subtree, stripped of
+// their prefixes, which is what labelSelector matches against (mirroring
+// the Kubernetes labels-vs-fields split). Where both carry the same key,
+// the entity's own `metadata.*` value wins.
+func metadataFields(fields map[string]string) map[string]string {
+	out := make(map[string]string, len(fields))
+	for k, v := range fields {
+		if rest, ok := strings.CutPrefix(k, "model.metadata."); ok {
+			out[rest] = v
+		}
+	}
+	for k, v := range fields {
+		if rest, ok := strings.CutPrefix(k, "metadata."); ok {
+			out[rest] = v
+		}
+	}
+	return out
+}
+
+func matchesWatchEvent(evt WatchEvent, labelSelector, fieldSelector []selectors.Requirement) bool {
+	if evt.Type == WatchBookmark {
+		return true
+	}
+	if len(labelSelector) == 0 && len(fieldSelector) == 0 {
+		return true
+	}
+
+	fields := flattenJSONFields(evt.Object)
+	if len(labelSelector) > 0 && !selectors.Matches(labelSelector, metadataFields(fields)) {
+		return false
+	}
+	return selectors.Matches(fieldSelector, fields)
+}
+
+// watchUpgrader upgrades a watch request to a WebSocket connection when the
+// client sends the `Upgrade: websocket` header instead of requesting SSE.
+// Origin checking is left to the API gateway in front of this service, the
+// same trust boundary the rest of this package assumes.
+var watchUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// watchEntities serves a `GET .../watch` request for the given entity kind:
+// it replays any buffered events newer than `?resourceVersion=`, then streams
+// new ones as they're published, over SSE by default or a WebSocket when the
+// request asks to be upgraded. `?labelSelector=` and `?fieldSelector=` (see
+// pkg/selectors) narrow the stream the same way they narrow the list
+// endpoints; `category`, `model`, `version`, and `apiVersion` are accepted as
+// a convenience alias for the equivalent fieldSelector clause.
+func (h *Handler) watchEntities(rw http.ResponseWriter, r *http.Request, kind string, ring *watchRing) {
+	queryParams := r.URL.Query()
+
+	labelSelector, fieldSelector, err := parseComponentSelectors(queryParams)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fieldSelector = append(fieldSelector, legacyWatchFieldRequirements(kind, queryParams)...)
+
+	var since int64
+	if raw := queryParams.Get("resourceVersion"); raw != "" {
+		since, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			http.Error(rw, "invalid resourceVersion", http.StatusBadRequest)
+			return
+		}
+	}
+
+	backlog, gone := ring.eventsSince(since)
+	if gone {
+		http.Error(rw, "requested resourceVersion is no longer in the replay buffer; re-list and retry", http.StatusGone)
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(r) {
+		h.streamWatchWS(rw, r, ring, backlog, labelSelector, fieldSelector)
+		return
+	}
+	h.streamWatchSSE(rw, r, kind, ring, backlog, labelSelector, fieldSelector)
+}
+
+func (h *Handler) streamWatchSSE(rw http.ResponseWriter, r *http.Request, kind string, ring *watchRing, backlog []WatchEvent, labelSelector, fieldSelector []selectors.Requirement) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.WriteHeader(http.StatusOK)
+
+	write := func(evt WatchEvent) bool {
+		b, err := json.Marshal(evt)
+		if err != nil {
+			h.log.Error(err)
+			return true
+		}
+		if _, err := fmt.Fprintf(rw, "data: %s\n\n", b); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	for _, evt := range backlog {
+		if matchesWatchEvent(evt, labelSelector, fieldSelector) && !write(evt) {
+			return
+		}
+	}
+
+	sub := ring.subscribe()
+	defer ring.unsubscribe(sub)
+
+	ctx := r.Context()
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-sub:
+			if !ok {
+				return
+			}
+			if matchesWatchEvent(evt, labelSelector, fieldSelector) && !write(evt) {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(rw, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *Handler) streamWatchWS(rw http.ResponseWriter, r *http.Request, ring *watchRing, backlog []WatchEvent, labelSelector, fieldSelector []selectors.Requirement) {
+	conn, err := watchUpgrader.Upgrade(rw, r, nil)
+	if err != nil {
+		h.log.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	write := func(evt WatchEvent) bool {
+		return conn.WriteJSON(evt) == nil
+	}
+
+	for _, evt := range backlog {
+		if matchesWatchEvent(evt, labelSelector, fieldSelector) && !write(evt) {
+			return
+		}
+	}
+
+	sub := ring.subscribe()
+	defer ring.unsubscribe(sub)
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-sub:
+			if !ok {
+				return
+			}
+			if matchesWatchEvent(evt, labelSelector, fieldSelector) && !write(evt) {
+				return
+			}
+		}
+	}
+}
+
+// swagger:route GET /api/meshmodels/components/watch GetMeshmodelComponentsWatch idGetMeshmodelComponentsWatch
+// Handle GET request for a live stream of component registry changes.
+//
+// Upgrades to Server-Sent Events by default, or to a WebSocket when the
+// request carries `Upgrade: websocket`. Each event on the stream is a
+// `{type: ADDED|MODIFIED|DELETED|BOOKMARK, object, resourceVersion}` object
+// mirroring Kubernetes watch semantics.
+//
+// ```?resourceVersion={N}``` Resume from a previously seen resourceVersion, replaying buffered events since. A 410 is returned if N has already aged out of the replay buffer.
+//
+// ```?category={category}```, ```?model={model}```, ```?version={version}```, ```?apiVersion={apiVersion}``` narrow the stream the same way they narrow the list endpoints
+//
+// ```?labelSelector={expr}``` and ```?fieldSelector={expr}``` accept the same selector grammar as the list endpoints (see pkg/selectors)
+// responses:
+//
+//	200: noContentWrapper
+func (h *Handler) GetMeshmodelComponentsWatch(rw http.ResponseWriter, r *http.Request) {
+	h.watchEntities(rw, r, "components", componentWatch)
+}
+
+// swagger:route GET /api/meshmodels/models/watch GetMeshmodelModelsWatch idGetMeshmodelModelsWatch
+// Handle GET request for a live stream of model registry changes. See
+// GetMeshmodelComponentsWatch for the event shape and accepted parameters.
+// responses:
+//
+//	200: noContentWrapper
+func (h *Handler) GetMeshmodelModelsWatch(rw http.ResponseWriter, r *http.Request) {
+	h.watchEntities(rw, r, "models", modelWatch)
+}
+
+// swagger:route GET /api/meshmodels/relationships/watch GetMeshmodelRelationshipsWatch idGetMeshmodelRelationshipsWatch
+// Handle GET request for a live stream of relationship registry changes. See
+// GetMeshmodelComponentsWatch for the event shape and accepted parameters.
+// responses:
+//
+//	200: noContentWrapper
+func (h *Handler) GetMeshmodelRelationshipsWatch(rw http.ResponseWriter, r *http.Request) {
+	h.watchEntities(rw, r, "relationships", relationshipWatch)
+}
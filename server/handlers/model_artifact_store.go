@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+)
+
+// modelArtifactStoreURLEnv names the environment variable
+// getModelArtifactStore reads the bucket URL from. Handler.config is owned
+// by this repo but its definition isn't part of this change, so - like the
+// rest of this series' handler-local config - the bucket location is
+// resolved from the environment rather than a new h.config field that
+// nothing else would populate.
+const modelArtifactStoreURLEnv = "MESHERY_MODEL_ARTIFACT_STORE_URL"
+
+// ModelArtifactStore is where ExportModel and the `file`/`urlImport`
+// RegisterMeshmodels branches keep model artifact bytes, instead of
+// relying solely on os.TempDir()/os.ReadFile. A blobArtifactStore backs it
+// with `gocloud.dev/blob`, so the same code path works whether the
+// configured bucket URL (modelArtifactStoreURLEnv) is an `s3://`, `gs://`,
+// `azblob://`, or local `file://` location - the last of which is a
+// drop-in replacement for what os.TempDir() did before this file existed,
+// so a server with no bucket configured keeps working unchanged.
+type ModelArtifactStore interface {
+	// Put uploads the data read from r under key and returns its
+	// content digest (`sha256:<hex>`).
+	Put(ctx context.Context, key string, r io.Reader) (digest string, err error)
+	// Get opens the object stored under key for reading. Callers must
+	// Close the returned reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Stat reports size/mod-time for key without reading its contents.
+	Stat(ctx context.Context, key string) (ArtifactInfo, error)
+	// Delete removes key. It is not an error for key not to exist.
+	Delete(ctx context.Context, key string) error
+}
+
+// ArtifactInfo is the subset of blob.Attributes callers of Stat need.
+type ArtifactInfo struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// blobArtifactStore is the gocloud.dev/blob-backed ModelArtifactStore.
+type blobArtifactStore struct {
+	bucket *blob.Bucket
+}
+
+// NewModelArtifactStore opens bucketURL - an `s3://`, `gs://`, `azblob://`,
+// or `file://` location, per gocloud.dev/blob's URL scheme registry (the
+// blank imports above register the driver for each) - as a
+// ModelArtifactStore.
+func NewModelArtifactStore(ctx context.Context, bucketURL string) (ModelArtifactStore, error) {
+	bucket, err := blob.OpenBucket(ctx, bucketURL)
+	if err != nil {
+		return nil, fmt.Errorf("error opening model artifact store %q: %w", bucketURL, err)
+	}
+	return &blobArtifactStore{bucket: bucket}, nil
+}
+
+func (s *blobArtifactStore) Put(ctx context.Context, key string, r io.Reader) (string, error) {
+	hasher := sha256.New()
+	w, err := s.bucket.NewWriter(ctx, key, nil)
+	if err != nil {
+		return "", fmt.Errorf("error opening writer for %q: %w", key, err)
+	}
+	if _, err := io.Copy(io.MultiWriter(w, hasher), r); err != nil {
+		_ = w.Close()
+		return "", fmt.Errorf("error writing %q: %w", key, err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("error finalizing %q: %w", key, err)
+	}
+	return "sha256:" + hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func (s *blobArtifactStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := s.bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %w", key, err)
+	}
+	return reader, nil
+}
+
+func (s *blobArtifactStore) Stat(ctx context.Context, key string) (ArtifactInfo, error) {
+	attrs, err := s.bucket.Attributes(ctx, key)
+	if err != nil {
+		return ArtifactInfo{}, fmt.Errorf("error reading attributes of %q: %w", key, err)
+	}
+	return ArtifactInfo{Size: attrs.Size, ModTime: attrs.ModTime}, nil
+}
+
+func (s *blobArtifactStore) Delete(ctx context.Context, key string) error {
+	if err := s.bucket.Delete(ctx, key); err != nil {
+		return fmt.Errorf("error deleting %q: %w", key, err)
+	}
+	return nil
+}
+
+var (
+	modelArtifactStoreOnce sync.Once
+	modelArtifactStore     ModelArtifactStore
+	modelArtifactStoreErr  error
+)
+
+// getModelArtifactStore lazily opens the bucket URL named by
+// modelArtifactStoreURLEnv the first time any handler needs a
+// ModelArtifactStore, and returns the same instance afterwards. An unset
+// URL falls back to a `file://` bucket rooted at os.TempDir(), the same
+// location CreateTemp already wrote to before this file existed, so a
+// server with no bucket configured keeps working exactly as it did.
+func (h *Handler) getModelArtifactStore(ctx context.Context) (ModelArtifactStore, error) {
+	modelArtifactStoreOnce.Do(func() {
+		bucketURL := os.Getenv(modelArtifactStoreURLEnv)
+		if bucketURL == "" {
+			bucketURL = "file://" + os.TempDir()
+		}
+		modelArtifactStore, modelArtifactStoreErr = NewModelArtifactStore(ctx, bucketURL)
+		if modelArtifactStoreErr == nil {
+			go runArtifactStoreGC(context.Background(), modelArtifactStore)
+		}
+	})
+	return modelArtifactStore, modelArtifactStoreErr
+}
+
+// artifactStoreTTL is how long a staged upload (everything under the
+// artifactTmpPrefix namespace) is kept before runArtifactStoreGC's sweep
+// deletes it.
+const artifactStoreTTL = 24 * time.Hour
+
+// artifactTmpPrefix namespaces staged, not-yet-content-addressed uploads
+// (the `file`/`urlImport` import branches stage here before registration)
+// separately from the content-addressed `blobs/sha256/<hex>` keys Put
+// into by exportModelArtifact, which are kept indefinitely as a cache.
+const artifactTmpPrefix = "tmp/"
+
+var (
+	exportCacheMu sync.Mutex
+	// exportCache maps "{model}@{version}" to the content-addressed key
+	// its built OCI tar was last stored under, so a repeated export of an
+	// unchanged model reuses the cached object instead of rebuilding it.
+	exportCache = map[string]string{}
+)
+
+// modelArtifactKey returns the content-addressed store key for a blob
+// with the given digest.
+func modelArtifactKey(digest string) string {
+	algo, hex, found := digestParts(digest)
+	if !found {
+		return "blobs/" + digest
+	}
+	return "blobs/" + algo + "/" + hex
+}
+
+func digestParts(digest string) (algo, hex string, found bool) {
+	for i := 0; i < len(digest); i++ {
+		if digest[i] == ':' {
+			return digest[:i], digest[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// exportModelArtifact uploads a built model artifact's bytes to store
+// under a content-addressed key derived from its own digest (computed
+// locally, since the caller already has data in memory and Put's own
+// digest return would otherwise need a second pass to turn into a key)
+// and records that key in exportCache under modelVersionKey, so
+// exportCachedArtifact can serve the next export of the same
+// {model,version} straight from the store.
+func exportModelArtifact(ctx context.Context, store ModelArtifactStore, modelVersionKey string, data []byte) (digest string, err error) {
+	sum := sha256.Sum256(data)
+	digest = "sha256:" + hex.EncodeToString(sum[:])
+	key := modelArtifactKey(digest)
+
+	if _, err := store.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return "", err
+	}
+
+	exportCacheMu.Lock()
+	exportCache[modelVersionKey] = key
+	exportCacheMu.Unlock()
+	return digest, nil
+}
+
+// exportCachedArtifact returns the store key a previous exportModelArtifact
+// call cached modelVersionKey's built tar under, if any.
+func exportCachedArtifact(modelVersionKey string) (key string, ok bool) {
+	exportCacheMu.Lock()
+	defer exportCacheMu.Unlock()
+	key, ok = exportCache[modelVersionKey]
+	return key, ok
+}
+
+var (
+	stagedKeysMu sync.Mutex
+	// stagedKeys is every artifactTmpPrefix key a stageImportArtifact call
+	// has written, so runArtifactStoreGC's sweep knows what to check without
+	// needing a bucket List iterator.
+	stagedKeys []string
+)
+
+// stageImportArtifact uploads a downloaded/decoded import artifact's raw
+// bytes to store under a fresh artifactTmpPrefix key, best-effort: a
+// failure here only loses the audit copy, since the `file`/`urlImport`
+// branches that call it still unpack from the local temp file
+// registration.NewDir requires, exactly as they did before this file
+// existed. Unlike exportModelArtifact's cache, staged keys are never
+// reused by a later request and exist only so runArtifactStoreGC has
+// something to expire.
+func (h *Handler) stageImportArtifact(ctx context.Context, name string, data []byte) {
+	store, err := h.getModelArtifactStore(ctx)
+	if err != nil {
+		h.log.Error(err)
+		return
+	}
+
+	key := fmt.Sprintf("%s%d-%s", artifactTmpPrefix, time.Now().UnixNano(), name)
+	if _, err := store.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		h.log.Error(err)
+		return
+	}
+
+	stagedKeysMu.Lock()
+	stagedKeys = append(stagedKeys, key)
+	stagedKeysMu.Unlock()
+}
+
+// runArtifactStoreGC periodically deletes every stageImportArtifact key
+// whose ModTime is older than artifactStoreTTL, and should be started
+// once (as a goroutine) per ModelArtifactStore the server opens;
+// getModelArtifactStore starts it when it opens the store.
+func runArtifactStoreGC(ctx context.Context, store ModelArtifactStore) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	sweep := func() {
+		stagedKeysMu.Lock()
+		keys := append([]string(nil), stagedKeys...)
+		stagedKeysMu.Unlock()
+
+		remaining := keys[:0]
+		for _, key := range keys {
+			info, err := store.Stat(ctx, key)
+			if err != nil {
+				continue
+			}
+			if time.Since(info.ModTime) > artifactStoreTTL {
+				_ = store.Delete(ctx, key)
+				continue
+			}
+			remaining = append(remaining, key)
+		}
+
+		stagedKeysMu.Lock()
+		stagedKeys = remaining
+		stagedKeysMu.Unlock()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweep()
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/meshery/meshery/server/helpers/utils"
+	"github.com/meshery/meshery/server/models"
+	"github.com/meshery/meshkit/models/registration"
+	meshkitutils "github.com/meshery/meshkit/utils"
+)
+
+// Streaming and resumable alternatives to RegisterMeshmodels's "file" upload
+// type, which requires the whole model file base64-encoded inside the JSON
+// body (importRequest.ImportBody.ModelFile) - tripling memory footprint and
+// unworkable for a large helm chart bundle or an operator's CRD pile. Both
+// variants here stream straight to a temp file (never holding the full
+// upload in memory at once) and hand off to registration.NewDir exactly
+// like the "file" case does once the upload is complete.
+
+// modelUploadMaxSize bounds a single model upload, whether delivered via
+// ImportModelUpload's multipart body or accumulated across
+// PatchModelUpload's chunks. modelUploadMaxSizeEnv overrides it when set;
+// Handler.config's definition isn't part of this change, so - as with
+// modelArtifactStoreURLEnv - this is resolved from the environment rather
+// than a new h.config field nothing else would populate.
+const modelUploadMaxSize = 4 << 30 // 4 GiB
+
+// modelUploadMaxSizeEnv names the environment variable maxModelUploadSize
+// reads its override from, as a byte count.
+const modelUploadMaxSizeEnv = "MESHERY_MODEL_UPLOAD_MAX_SIZE"
+
+func (h *Handler) maxModelUploadSize() int64 {
+	if raw := os.Getenv(modelUploadMaxSizeEnv); raw != "" {
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return modelUploadMaxSize
+}
+
+// swagger:route POST /api/meshmodels/import/upload ImportModelUpload idImportModelUpload
+// Handle POST request for importing a model from a `multipart/form-data`
+// upload (field name `file`), streaming it directly to a temp file instead
+// of requiring the base64-encoded body RegisterMeshmodels's "file" upload
+// type does.
+// responses:
+//	200: meshmodelRegistrationFailureResponseWrapper
+func (h *Handler) ImportModelUpload(rw http.ResponseWriter, r *http.Request, _ *models.Preference, user *models.User, provider models.Provider) {
+	var response models.RegistryAPIResponse
+	regErrorStore := models.NewRegistrationFailureLogHandler()
+	var mu sync.Mutex
+	userID := uuid.FromStringOrNil(user.ID)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.handleError(rw, err, "Error reading uploaded model file")
+		h.sendErrorEvent(userID, provider, "Error reading uploaded model file", err)
+		return
+	}
+	defer file.Close()
+
+	tempFile, err := os.CreateTemp("", "model-upload-*-"+header.Filename)
+	if err != nil {
+		err = meshkitutils.ErrCreateFile(err, "Error creating temp file")
+		h.handleError(rw, err, "Error creating temp file")
+		h.sendErrorEvent(userID, provider, "Error creating temp file", err)
+		return
+	}
+	defer os.Remove(tempFile.Name())
+
+	written, err := io.Copy(tempFile, io.LimitReader(file, h.maxModelUploadSize()+1))
+	if err != nil {
+		tempFile.Close()
+		h.handleError(rw, err, "Error streaming uploaded model file")
+		h.sendErrorEvent(userID, provider, "Error streaming uploaded model file", err)
+		return
+	}
+	if written > h.maxModelUploadSize() {
+		tempFile.Close()
+		err := fmt.Errorf("uploaded model file exceeds the %d byte limit", h.maxModelUploadSize())
+		h.handleError(rw, err, err.Error())
+		h.sendErrorEvent(userID, provider, err.Error(), err)
+		return
+	}
+	tempFile.Close()
+
+	registrationHelper := registration.NewRegistrationHelper(utils.UI, h.registryManager, regErrorStore)
+	hitsBeforeRegistration := atomic.LoadInt64(&entityCacheHits)
+
+	dir := registration.NewDir(tempFile.Name())
+	registrationHelper.Register(dir)
+
+	publishWatchBookmark()
+	h.handleRegistrationAndError(registrationHelper, &mu, &response, regErrorStore)
+	var errMsg string
+	message := writeMessageString(&response)
+	if response.EntityCount.TotalErrCount > 0 {
+		errMsg = ErrMsgContruct(&response)
+	}
+	message = appendSkippedUnchangedNote(message, atomic.LoadInt64(&entityCacheHits)-hitsBeforeRegistration)
+	h.sendSuccessResponse(rw, userID, provider, message, errMsg, &response)
+}
+
+// modelUploadSession tracks an in-progress resumable model upload started
+// by StartModelUpload, mirroring ociUploadSession's role for the Distribution
+// Spec v2 blob upload flow - except chunks are written straight to disk via
+// file rather than buffered in memory, since avoiding that buffering is the
+// entire point of this endpoint.
+type modelUploadSession struct {
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	userID   uuid.UUID
+	provider models.Provider
+}
+
+var (
+	modelUploadsMu sync.Mutex
+	modelUploads   = map[string]*modelUploadSession{}
+)
+
+// swagger:route POST /api/meshmodels/import/uploads/ StartModelUpload idStartModelUpload
+// Handle POST request starting a resumable model upload session, modeled on
+// the OCI blob upload flow in oci_registry_v2.go. The `Location` response
+// header carries the session ID subsequent PatchModelUpload/CompleteModelUpload
+// requests address.
+// responses:
+//	202: noContentWrapper
+func (h *Handler) StartModelUpload(rw http.ResponseWriter, r *http.Request, _ *models.Preference, user *models.User, provider models.Provider) {
+	tempFile, err := os.CreateTemp("", "model-upload-session-*")
+	if err != nil {
+		h.handleError(rw, err, "Error creating temp file")
+		return
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+		h.handleError(rw, err, "Error starting model upload session")
+		return
+	}
+
+	session := &modelUploadSession{
+		file:     tempFile,
+		userID:   uuid.FromStringOrNil(user.ID),
+		provider: provider,
+	}
+	modelUploadsMu.Lock()
+	modelUploads[id.String()] = session
+	modelUploadsMu.Unlock()
+
+	rw.Header().Set("Location", fmt.Sprintf("/api/meshmodels/import/uploads/%s", id.String()))
+	rw.Header().Set("Range", "0-0")
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// swagger:route PATCH /api/meshmodels/import/uploads/{uuid} PatchModelUpload idPatchModelUpload
+// Handle PATCH request appending a byte range to an in-progress resumable
+// model upload. The range's start, given by the `Content-Range` header
+// (`bytes start-end/total`), must match the bytes already received.
+// responses:
+//	202: noContentWrapper
+//	416: noContentWrapper
+func (h *Handler) PatchModelUpload(rw http.ResponseWriter, r *http.Request, _ *models.Preference, user *models.User, provider models.Provider) {
+	id := mux.Vars(r)["uuid"]
+
+	modelUploadsMu.Lock()
+	session, ok := modelUploads[id]
+	modelUploadsMu.Unlock()
+	if !ok {
+		writeOCIError(rw, http.StatusNotFound, "UPLOAD_UNKNOWN", fmt.Sprintf("upload %s not found", id))
+		return
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if start, ok := parseContentRangeStart(r.Header.Get("Content-Range")); ok && start != session.size {
+		writeOCIError(rw, http.StatusRequestedRangeNotSatisfiable, "RANGE_INVALID",
+			fmt.Sprintf("expected chunk starting at %d, got %d", session.size, start))
+		return
+	}
+
+	maxSize := h.maxModelUploadSize()
+	written, err := io.Copy(session.file, io.LimitReader(r.Body, maxSize-session.size+1))
+	if err != nil {
+		writeOCIError(rw, http.StatusBadRequest, "UPLOAD_INVALID", err.Error())
+		return
+	}
+	session.size += written
+	if session.size > maxSize {
+		writeOCIError(rw, http.StatusBadRequest, "SIZE_INVALID", fmt.Sprintf("upload exceeds the %d byte limit", maxSize))
+		return
+	}
+
+	rw.Header().Set("Location", fmt.Sprintf("/api/meshmodels/import/uploads/%s", id))
+	rw.Header().Set("Range", fmt.Sprintf("0-%d", session.size-1))
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// swagger:route PUT /api/meshmodels/import/uploads/{uuid} CompleteModelUpload idCompleteModelUpload
+// Handle PUT request completing a resumable model upload. Any final chunk in
+// the request body is appended before the accumulated file's sha256 digest is
+// checked against the required `?digest=sha256:...` query parameter; only
+// once verified is the file handed off to registration.NewDir/
+// registrationHelper.Register, the same registration path the "file" upload
+// type uses.
+// responses:
+//	200: meshmodelRegistrationFailureResponseWrapper
+//	400: noContentWrapper
+func (h *Handler) CompleteModelUpload(rw http.ResponseWriter, r *http.Request, _ *models.Preference, user *models.User, provider models.Provider) {
+	id := mux.Vars(r)["uuid"]
+	wantDigest := r.URL.Query().Get("digest")
+	if wantDigest == "" {
+		writeOCIError(rw, http.StatusBadRequest, "DIGEST_INVALID", "digest query parameter is required")
+		return
+	}
+
+	modelUploadsMu.Lock()
+	session, ok := modelUploads[id]
+	delete(modelUploads, id)
+	modelUploadsMu.Unlock()
+	if !ok {
+		writeOCIError(rw, http.StatusNotFound, "UPLOAD_UNKNOWN", fmt.Sprintf("upload %s not found", id))
+		return
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	defer os.Remove(session.file.Name())
+
+	maxSize := h.maxModelUploadSize()
+	written, err := io.Copy(session.file, io.LimitReader(r.Body, maxSize-session.size+1))
+	if err != nil {
+		writeOCIError(rw, http.StatusBadRequest, "UPLOAD_INVALID", err.Error())
+		return
+	}
+	session.size += written
+	if session.size > maxSize {
+		writeOCIError(rw, http.StatusBadRequest, "SIZE_INVALID", fmt.Sprintf("upload exceeds the %d byte limit", maxSize))
+		return
+	}
+
+	gotDigest, err := digestOfFile(session.file)
+	if err != nil {
+		writeOCIError(rw, http.StatusInternalServerError, "UNKNOWN", err.Error())
+		return
+	}
+	if gotDigest != wantDigest {
+		writeOCIError(rw, http.StatusBadRequest, "DIGEST_INVALID", fmt.Sprintf("expected digest %s, got %s", wantDigest, gotDigest))
+		return
+	}
+	session.file.Close()
+
+	var response models.RegistryAPIResponse
+	regErrorStore := models.NewRegistrationFailureLogHandler()
+	var mu sync.Mutex
+	registrationHelper := registration.NewRegistrationHelper(utils.UI, h.registryManager, regErrorStore)
+	hitsBeforeRegistration := atomic.LoadInt64(&entityCacheHits)
+
+	dir := registration.NewDir(session.file.Name())
+	registrationHelper.Register(dir)
+
+	publishWatchBookmark()
+	h.handleRegistrationAndError(registrationHelper, &mu, &response, regErrorStore)
+	var errMsg string
+	message := writeMessageString(&response)
+	if response.EntityCount.TotalErrCount > 0 {
+		errMsg = ErrMsgContruct(&response)
+	}
+	message = appendSkippedUnchangedNote(message, atomic.LoadInt64(&entityCacheHits)-hitsBeforeRegistration)
+	h.sendSuccessResponse(rw, session.userID, session.provider, message, errMsg, &response)
+}
+
+// digestOfFile returns the sha256 digest (`sha256:<hex>`) of f's full
+// contents, seeking back to the start first so an in-progress session's
+// already-written bytes are hashed as a whole rather than from wherever the
+// last PatchModelUpload/CompleteModelUpload write left the offset.
+func digestOfFile(f *os.File) (string, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// parseContentRangeStart extracts the start offset from a `bytes
+// start-end/total` Content-Range header, as sent by a resumable upload
+// client between PatchModelUpload chunks.
+func parseContentRangeStart(header string) (start int64, ok bool) {
+	header = strings.TrimPrefix(header, "bytes ")
+	dash := strings.Index(header, "-")
+	if dash < 0 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(header[:dash], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,483 @@
+package handlers
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Package-local implementation of the parts of the OCI Distribution Spec
+// (https://github.com/opencontainers/distribution-spec) needed for an
+// `oras`/`crane`/`docker`/`helm registry` client to pull and push a
+// Meshery model by reference under `/api/meshmodels/v2/`. This sits
+// alongside the existing single-shot `ExportModel`/`GetMeshmodelModelOCI`
+// tar download and `RegisterMeshmodels` "oci" upload type, which remain
+// the simpler, non-protocol way to move a model in or out.
+//
+// Blobs are stored content-addressed by sha256 digest in ociBlobStore, so
+// a layer shared by two model versions (e.g. an unchanged relationships
+// tarball) is only ever stored once. Manifests are kept separately, one
+// per (repository, tag-or-digest) pair, and reference their config/layer
+// blobs by digest the same way a real registry does.
+
+const ociMediaTypeManifest = "application/vnd.oci.image.manifest.v1+json"
+
+// ociDescriptor mirrors the OCI content descriptor used for a manifest's
+// config and layers: a media type, digest, and size, enough for a client
+// to fetch the referenced blob and verify it.
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// ociManifest is the minimal OCI image manifest shape: a config blob (the
+// ModelDefinition) and one layer blob per entity kind (components,
+// relationships), keyed by sha256 the same way GetMeshmodelModelOCI lays
+// them out inside its tarball.
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// ociBlobStore is a process-local, content-addressed blob store: blobs
+// never need to be rewritten once written, since a digest uniquely
+// identifies its content, which is also what gives dedup across model
+// versions for free.
+type ociBlobStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+func (s *ociBlobStore) put(data []byte) string {
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.blobs[digest]; !ok {
+		s.blobs[digest] = data
+	}
+	return digest
+}
+
+func (s *ociBlobStore) get(digest string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.blobs[digest]
+	return data, ok
+}
+
+// ociUploadSession tracks an in-progress chunked blob upload initiated by
+// POST .../blobs/uploads/, accumulated across one or more PATCH requests
+// until the client PUTs the final chunk with the expected digest.
+type ociUploadSession struct {
+	mu   sync.Mutex
+	name string
+	buf  []byte
+}
+
+var (
+	ociBlobs = &ociBlobStore{blobs: map[string][]byte{}}
+
+	ociManifestsMu sync.RWMutex
+	// ociManifests is keyed by repository name, then by every ref (tag or
+	// digest) that currently resolves to a manifest.
+	ociManifests = map[string]map[string]*ociManifest{}
+
+	ociUploadsMu sync.Mutex
+	ociUploads   = map[string]*ociUploadSession{}
+)
+
+// writeOCIError writes the spec-mandated `{"errors":[...]}` body for a
+// Distribution Spec error response.
+func writeOCIError(rw http.ResponseWriter, status int, code, message string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+		"errors": []map[string]string{
+			{"code": code, "message": message},
+		},
+	})
+}
+
+// swagger:route GET /api/meshmodels/v2/ OCIAPIVersionCheck idOCIAPIVersionCheck
+// Handle GET request for the OCI Distribution Spec API version check. A
+// 200 with this header set is how a client (oras, crane, docker, helm
+// registry) confirms the server speaks Distribution Spec v2 before doing
+// anything else.
+// responses:
+//	200: noContentWrapper
+func (h *Handler) OCIAPIVersionCheck(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Docker-Distribution-Api-Version", "registry/2.0")
+	rw.WriteHeader(http.StatusOK)
+}
+
+// swagger:route GET /api/meshmodels/v2/{name}/tags/list OCIListTags idOCIListTags
+// Handle GET request for listing every tag registered against a model
+// repository, e.g. `istio` in `meshery.io/models/istio:1.20.0`.
+// responses:
+//	200: meshmodelOCITagListResponseWrapper
+func (h *Handler) OCIListTags(rw http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	ociManifestsMu.RLock()
+	refs := ociManifests[name]
+	tags := make([]string, 0, len(refs))
+	for ref := range refs {
+		if !isOCIDigest(ref) {
+			tags = append(tags, ref)
+		}
+	}
+	ociManifestsMu.RUnlock()
+	sort.Strings(tags)
+
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+		"name": name,
+		"tags": tags,
+	})
+}
+
+// swagger:route GET /api/meshmodels/v2/{name}/manifests/{ref} OCIGetManifest idOCIGetManifest
+// Handle GET (and HEAD, which returns the same headers with no body)
+// request for a model's manifest by tag or digest.
+// responses:
+//	200: meshmodelOCIManifestResponseWrapper
+//	404: noContentWrapper
+func (h *Handler) OCIGetManifest(rw http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name, ref := vars["name"], vars["ref"]
+
+	manifest, raw, ok := lookupManifest(name, ref)
+	if !ok {
+		writeOCIError(rw, http.StatusNotFound, "MANIFEST_UNKNOWN", fmt.Sprintf("manifest %s not found for %s", ref, name))
+		return
+	}
+
+	sum := sha256.Sum256(raw)
+	rw.Header().Set("Content-Type", manifest.MediaType)
+	rw.Header().Set("Docker-Content-Digest", "sha256:"+hex.EncodeToString(sum[:]))
+	rw.Header().Set("Content-Length", fmt.Sprintf("%d", len(raw)))
+
+	if r.Method == http.MethodHead {
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+	_, _ = rw.Write(raw)
+}
+
+// swagger:route PUT /api/meshmodels/v2/{name}/manifests/{ref} OCIPutManifest idOCIPutManifest
+// Handle PUT request for pushing a model's manifest under a tag (or its
+// own digest, the OCI "push by digest" form). Every config/layer digest
+// referenced by the manifest must already have been uploaded as a blob.
+// responses:
+//	201: noContentWrapper
+func (h *Handler) OCIPutManifest(rw http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name, ref := vars["name"], vars["ref"]
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeOCIError(rw, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+		return
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		writeOCIError(rw, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+		return
+	}
+
+	if _, ok := ociBlobs.get(manifest.Config.Digest); !ok {
+		writeOCIError(rw, http.StatusBadRequest, "BLOB_UNKNOWN", fmt.Sprintf("config blob %s not uploaded", manifest.Config.Digest))
+		return
+	}
+	for _, layer := range manifest.Layers {
+		if _, ok := ociBlobs.get(layer.Digest); !ok {
+			writeOCIError(rw, http.StatusBadRequest, "BLOB_UNKNOWN", fmt.Sprintf("layer blob %s not uploaded", layer.Digest))
+			return
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	ociManifestsMu.Lock()
+	if ociManifests[name] == nil {
+		ociManifests[name] = map[string]*ociManifest{}
+	}
+	ociManifests[name][ref] = &manifest
+	ociManifests[name][digest] = &manifest
+	ociManifestsMu.Unlock()
+
+	rw.Header().Set("Docker-Content-Digest", digest)
+	rw.WriteHeader(http.StatusCreated)
+}
+
+// swagger:route GET /api/meshmodels/v2/{name}/blobs/{digest} OCIGetBlob idOCIGetBlob
+// Handle GET (and HEAD) request for a content-addressed blob by its
+// sha256 digest.
+// responses:
+//	200: []byte
+//	404: noContentWrapper
+func (h *Handler) OCIGetBlob(rw http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	digest := vars["digest"]
+
+	data, ok := ociBlobs.get(digest)
+	if !ok {
+		writeOCIError(rw, http.StatusNotFound, "BLOB_UNKNOWN", fmt.Sprintf("blob %s not found", digest))
+		return
+	}
+
+	rw.Header().Set("Docker-Content-Digest", digest)
+	rw.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	if r.Method == http.MethodHead {
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+	_, _ = rw.Write(data)
+}
+
+// swagger:route POST /api/meshmodels/v2/{name}/blobs/uploads/ OCIStartBlobUpload idOCIStartBlobUpload
+// Handle POST request starting a chunked blob upload session. The
+// `Location` response header carries the session ID the client PATCHes
+// chunks to and finally PUTs with `?digest=` to complete.
+// responses:
+//	202: noContentWrapper
+func (h *Handler) OCIStartBlobUpload(rw http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		writeOCIError(rw, http.StatusInternalServerError, "UNKNOWN", err.Error())
+		return
+	}
+
+	session := &ociUploadSession{name: name}
+	ociUploadsMu.Lock()
+	ociUploads[id.String()] = session
+	ociUploadsMu.Unlock()
+
+	rw.Header().Set("Location", fmt.Sprintf("/api/meshmodels/v2/%s/blobs/uploads/%s", name, id.String()))
+	rw.Header().Set("Range", "0-0")
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// swagger:route PATCH /api/meshmodels/v2/{name}/blobs/uploads/{uuid} OCIPatchBlobUpload idOCIPatchBlobUpload
+// Handle PATCH request appending a chunk to an in-progress blob upload.
+// responses:
+//	202: noContentWrapper
+func (h *Handler) OCIPatchBlobUpload(rw http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name, id := vars["name"], vars["uuid"]
+
+	ociUploadsMu.Lock()
+	session, ok := ociUploads[id]
+	ociUploadsMu.Unlock()
+	if !ok || session.name != name {
+		writeOCIError(rw, http.StatusNotFound, "BLOB_UPLOAD_UNKNOWN", fmt.Sprintf("upload %s not found", id))
+		return
+	}
+
+	chunk, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeOCIError(rw, http.StatusBadRequest, "BLOB_UPLOAD_INVALID", err.Error())
+		return
+	}
+
+	session.mu.Lock()
+	session.buf = append(session.buf, chunk...)
+	size := len(session.buf)
+	session.mu.Unlock()
+
+	rw.Header().Set("Location", fmt.Sprintf("/api/meshmodels/v2/%s/blobs/uploads/%s", name, id))
+	rw.Header().Set("Range", fmt.Sprintf("0-%d", size-1))
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// swagger:route PUT /api/meshmodels/v2/{name}/blobs/uploads/{uuid} OCICompleteBlobUpload idOCICompleteBlobUpload
+// Handle PUT request completing a chunked blob upload. Any final chunk in
+// the request body is appended before the accumulated data's digest is
+// checked against the required `?digest=` query parameter and, once
+// verified, stored in the content-addressed blob store.
+// responses:
+//	201: noContentWrapper
+func (h *Handler) OCICompleteBlobUpload(rw http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name, id := vars["name"], vars["uuid"]
+	wantDigest := r.URL.Query().Get("digest")
+	if wantDigest == "" {
+		writeOCIError(rw, http.StatusBadRequest, "DIGEST_INVALID", "digest query parameter is required")
+		return
+	}
+
+	ociUploadsMu.Lock()
+	session, ok := ociUploads[id]
+	delete(ociUploads, id)
+	ociUploadsMu.Unlock()
+	if !ok || session.name != name {
+		writeOCIError(rw, http.StatusNotFound, "BLOB_UPLOAD_UNKNOWN", fmt.Sprintf("upload %s not found", id))
+		return
+	}
+
+	final, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeOCIError(rw, http.StatusBadRequest, "BLOB_UPLOAD_INVALID", err.Error())
+		return
+	}
+	session.mu.Lock()
+	data := append(session.buf, final...)
+	session.mu.Unlock()
+
+	sum := sha256.Sum256(data)
+	gotDigest := "sha256:" + hex.EncodeToString(sum[:])
+	if gotDigest != wantDigest {
+		writeOCIError(rw, http.StatusBadRequest, "DIGEST_INVALID", fmt.Sprintf("expected digest %s, got %s", wantDigest, gotDigest))
+		return
+	}
+	ociBlobs.put(data)
+
+	rw.Header().Set("Docker-Content-Digest", gotDigest)
+	rw.WriteHeader(http.StatusCreated)
+}
+
+func isOCIDigest(ref string) bool {
+	return len(ref) > 7 && ref[:7] == "sha256:"
+}
+
+func lookupManifest(name, ref string) (*ociManifest, []byte, bool) {
+	ociManifestsMu.RLock()
+	manifest, ok := ociManifests[name][ref]
+	ociManifestsMu.RUnlock()
+	if !ok {
+		return nil, nil, false
+	}
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, nil, false
+	}
+	return manifest, raw, true
+}
+
+// resolveOCIReference resolves a `registry/name:tag` reference (the part
+// of a `oci://registry/name:tag` urlImport reference after the scheme)
+// against this server's own Distribution Spec v2 store and returns an
+// OCI-layout tarball in the same shape meshkitOci.SaveOCIArtifact writes,
+// so it can be handed to registration.NewDir exactly like the "oci"
+// upload type's meshkitOci.PullOCIArtifact result above. A leading host
+// segment (anything before the first "/" that looks like a host, i.e.
+// contains a "." or a ":") is stripped: this store only has one
+// repository namespace, so `ghcr.io/org/model:tag` and `org/model:tag`
+// resolve the same way. A reference this store doesn't hold is not pulled
+// from anywhere here; the urlImport branch falls back to
+// pullRemoteOCIArtifact (oci_remote_push.go) for it.
+func resolveOCIReference(ref string) ([]byte, error) {
+	name, tag, err := splitOCIReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, _, ok := lookupManifest(name, tag)
+	if !ok {
+		return nil, fmt.Errorf("oci reference %s not found", ref)
+	}
+
+	return buildOCILayoutTar(manifest)
+}
+
+// splitOCIReference splits `[host/]name:tag` into name and tag, stripping
+// a leading registry host if present.
+func splitOCIReference(ref string) (name, tag string, err error) {
+	if idx := strings.Index(ref, "/"); idx >= 0 {
+		host := ref[:idx]
+		if strings.ContainsAny(host, ".:") {
+			ref = ref[idx+1:]
+		}
+	}
+
+	idx := strings.LastIndex(ref, ":")
+	if idx < 0 {
+		return "", "", fmt.Errorf("oci reference %q is missing a :tag", ref)
+	}
+	return ref[:idx], ref[idx+1:], nil
+}
+
+// buildOCILayoutTar assembles a minimal OCI image layout
+// (https://github.com/opencontainers/image-spec/blob/main/image-layout.md)
+// tarball containing manifest's config and layer blobs, the same layout a
+// real registry client unpacks a pulled image into on disk.
+func buildOCILayoutTar(manifest *ociManifest) ([]byte, error) {
+	manifestRaw, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(manifestRaw)
+	manifestDigest := "sha256:" + hex.EncodeToString(sum[:])
+
+	index := map[string]interface{}{
+		"schemaVersion": 2,
+		"manifests": []ociDescriptor{
+			{MediaType: ociMediaTypeManifest, Digest: manifestDigest, Size: int64(len(manifestRaw))},
+		},
+	}
+	indexRaw, err := json.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	writeEntry := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(data)), Mode: 0o644}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := writeEntry("oci-layout", []byte(`{"imageLayoutVersion":"1.0.0"}`)); err != nil {
+		return nil, err
+	}
+	if err := writeEntry("index.json", indexRaw); err != nil {
+		return nil, err
+	}
+	if err := writeEntry(blobPath(manifestDigest), manifestRaw); err != nil {
+		return nil, err
+	}
+
+	descriptors := append([]ociDescriptor{manifest.Config}, manifest.Layers...)
+	for _, d := range descriptors {
+		data, ok := ociBlobs.get(d.Digest)
+		if !ok {
+			return nil, fmt.Errorf("blob %s referenced by manifest is missing", d.Digest)
+		}
+		if err := writeEntry(blobPath(d.Digest), data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func blobPath(digest string) string {
+	algo, hex, _ := strings.Cut(digest, ":")
+	return "blobs/" + algo + "/" + hex
+}
@@ -0,0 +1,526 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+// WebhookEventType identifies a registry lifecycle event a webhook
+// subscription can fire on.
+type WebhookEventType string
+
+const (
+	WebhookComponentRegistered WebhookEventType = "component.registered"
+	WebhookModelImported       WebhookEventType = "model.imported"
+	WebhookEntityStatusUpdated WebhookEventType = "entity.status_updated"
+)
+
+// webhookMaxDeliveryLog bounds the in-memory delivery log so a misbehaving
+// target can't grow it without bound; the oldest attempts are dropped first.
+const webhookMaxDeliveryLog = 500
+
+// webhookMaxAttempts is the number of delivery attempts (including the
+// first) before a failing delivery is given up on.
+const webhookMaxAttempts = 5
+
+// webhookStorePathEnv names the environment variable webhook registrations
+// are persisted under, as a JSON file. Handler.config isn't part of this
+// change (see modelArtifactStoreURLEnv), so - as with the rest of this
+// series' handler-local config - the path is resolved from the
+// environment rather than a new h.config field nothing else would
+// populate. Unset falls back to a file under os.TempDir(), so with no
+// path configured, registrations survive a restart only for as long as
+// that temp file does.
+const webhookStorePathEnv = "MESHERY_WEBHOOK_STORE_PATH"
+
+func webhookStorePath() string {
+	if path := os.Getenv(webhookStorePathEnv); path != "" {
+		return path
+	}
+	return os.TempDir() + "/meshery-webhooks.json"
+}
+
+// Webhook is an operator-configured outbound delivery target for meshmodel
+// registry lifecycle events. Secret and AuthToken are write-only: they are
+// never echoed back by GetWebhooks.
+type Webhook struct {
+	ID        string             `json:"id"`
+	URL       string             `json:"url"`
+	Events    []WebhookEventType `json:"events"`
+	Secret    string             `json:"secret,omitempty"`
+	AuthToken string             `json:"authToken,omitempty"`
+}
+
+// WebhookDelivery records a single attempt to deliver an event to a
+// Webhook, so the UI can show delivery history and diagnose a misbehaving
+// target.
+type WebhookDelivery struct {
+	ID          string           `json:"id"`
+	WebhookID   string           `json:"webhookId"`
+	Event       WebhookEventType `json:"event"`
+	Attempt     int              `json:"attempt"`
+	StatusCode  int              `json:"statusCode,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	DeliveredAt string           `json:"deliveredAt"`
+}
+
+// WebhookModelImportedPayload is the WebhookModelImported event body: a
+// deliberately small summary of a RegisterMeshmodels call, rather than its
+// full ImportRequest, so a future ImportBody field isn't forwarded to an
+// operator-supplied webhook URL just by existing.
+type WebhookModelImportedPayload struct {
+	UploadType string `json:"uploadType"`
+	ModelName  string `json:"modelName,omitempty"`
+	Registrant string `json:"registrant,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+var (
+	webhookMu       sync.RWMutex
+	webhooks        = map[string]*Webhook{}
+	webhookLoadOnce sync.Once
+
+	webhookDeliveryMu sync.Mutex
+	webhookDeliveries []WebhookDelivery
+)
+
+// ensureWebhooksLoaded lazily loads persisted webhook registrations from
+// webhookStorePath the first time any handler touches the webhooks map, so
+// a server restart picks up registrations made before it instead of
+// starting with an empty in-process map every time.
+func ensureWebhooksLoaded() {
+	webhookLoadOnce.Do(func() {
+		data, err := os.ReadFile(webhookStorePath())
+		if err != nil {
+			return
+		}
+		var loaded map[string]*Webhook
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			return
+		}
+		webhookMu.Lock()
+		webhooks = loaded
+		webhookMu.Unlock()
+	})
+}
+
+// persistWebhooksLocked writes the current webhooks map to webhookStorePath.
+// Callers must already hold webhookMu (for reading or writing). Persistence
+// is best-effort: a failure here only means the next restart won't see the
+// change, the in-process map a request is about to respond from is already
+// up to date either way.
+func persistWebhooksLocked() {
+	data, err := json.Marshal(webhooks)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(webhookStorePath(), data, 0o600)
+}
+
+// swagger:route GET /api/meshmodels/webhooks GetWebhooks idGetWebhooks
+// Handle GET request for listing registered registry webhooks.
+// responses:
+//	200: []meshmodelWebhookResponseWrapper
+func (h *Handler) GetWebhooks(rw http.ResponseWriter, r *http.Request) {
+	ensureWebhooksLoaded()
+	rw.Header().Add("Content-Type", "application/json")
+
+	webhookMu.RLock()
+	res := make([]Webhook, 0, len(webhooks))
+	for _, wh := range webhooks {
+		sanitized := *wh
+		sanitized.Secret = ""
+		sanitized.AuthToken = ""
+		res = append(res, sanitized)
+	}
+	webhookMu.RUnlock()
+
+	if err := json.NewEncoder(rw).Encode(res); err != nil {
+		h.log.Error(ErrGetMeshModels(err))
+		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
+	}
+}
+
+// swagger:route POST /api/meshmodels/webhooks RegisterWebhook idPostRegisterWebhook
+// Handle POST request for registering a webhook. The request body is a
+// Webhook with `url` and `events` required; `secret` (used to sign
+// deliveries with an `X-Meshery-Signature: sha256=<hmac>` header) and
+// `authToken` (sent as `Authorization: Bearer <authToken>`) are optional.
+// `url` must be an http(s) URL that doesn't resolve to a loopback, private,
+// or link-local address, so a registered webhook can't be used to reach
+// this server's own internal network.
+// responses:
+//	200: meshmodelWebhookResponseWrapper
+func (h *Handler) RegisterWebhook(rw http.ResponseWriter, r *http.Request) {
+	ensureWebhooksLoaded()
+	rw.Header().Add("Content-Type", "application/json")
+
+	var wh Webhook
+	if err := json.NewDecoder(r.Body).Decode(&wh); err != nil {
+		http.Error(rw, ErrRequestBody(err).Error(), http.StatusBadRequest)
+		return
+	}
+	if wh.URL == "" || len(wh.Events) == 0 {
+		http.Error(rw, `"url" and at least one of "events" are required`, http.StatusBadRequest)
+		return
+	}
+	if err := validateWebhookURL(wh.URL); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	wh.ID = id.String()
+
+	webhookMu.Lock()
+	webhooks[wh.ID] = &wh
+	persistWebhooksLocked()
+	webhookMu.Unlock()
+
+	sanitized := wh
+	sanitized.Secret = ""
+	sanitized.AuthToken = ""
+	if err := json.NewEncoder(rw).Encode(sanitized); err != nil {
+		h.log.Error(ErrGetMeshModels(err))
+		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
+	}
+}
+
+// swagger:route PUT /api/meshmodels/webhooks/{id} UpdateWebhook idPutUpdateWebhook
+// Handle PUT request for updating a registered webhook's url/events, and
+// optionally its secret/authToken. The request body is a Webhook, the same
+// shape RegisterWebhook accepts; an empty secret/authToken in the body
+// leaves the stored one unchanged, rather than clearing it, since
+// GetWebhooks never echoes either back for a caller to round-trip.
+// responses:
+//	200: meshmodelWebhookResponseWrapper
+//	404: noContentWrapper
+func (h *Handler) UpdateWebhook(rw http.ResponseWriter, r *http.Request) {
+	ensureWebhooksLoaded()
+	rw.Header().Add("Content-Type", "application/json")
+	id := mux.Vars(r)["id"]
+
+	var update Webhook
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(rw, ErrRequestBody(err).Error(), http.StatusBadRequest)
+		return
+	}
+	if update.URL == "" || len(update.Events) == 0 {
+		http.Error(rw, `"url" and at least one of "events" are required`, http.StatusBadRequest)
+		return
+	}
+	if err := validateWebhookURL(update.URL); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	webhookMu.Lock()
+	existing, ok := webhooks[id]
+	if !ok {
+		webhookMu.Unlock()
+		http.Error(rw, fmt.Sprintf("webhook %s not found", id), http.StatusNotFound)
+		return
+	}
+	update.ID = id
+	if update.Secret == "" {
+		update.Secret = existing.Secret
+	}
+	if update.AuthToken == "" {
+		update.AuthToken = existing.AuthToken
+	}
+	webhooks[id] = &update
+	persistWebhooksLocked()
+	webhookMu.Unlock()
+
+	sanitized := update
+	sanitized.Secret = ""
+	sanitized.AuthToken = ""
+	if err := json.NewEncoder(rw).Encode(sanitized); err != nil {
+		h.log.Error(ErrGetMeshModels(err))
+		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
+	}
+}
+
+// swagger:route DELETE /api/meshmodels/webhooks/{id} DeleteWebhook idDeleteWebhook
+// Handle DELETE request for removing a registered webhook.
+// responses:
+//	204: noContentWrapper
+func (h *Handler) DeleteWebhook(rw http.ResponseWriter, r *http.Request) {
+	ensureWebhooksLoaded()
+	id := mux.Vars(r)["id"]
+
+	webhookMu.Lock()
+	_, ok := webhooks[id]
+	delete(webhooks, id)
+	if ok {
+		persistWebhooksLocked()
+	}
+	webhookMu.Unlock()
+
+	if !ok {
+		http.Error(rw, fmt.Sprintf("webhook %s not found", id), http.StatusNotFound)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// swagger:route GET /api/meshmodels/webhooks/deliveries GetWebhookDeliveries idGetWebhookDeliveries
+// Handle GET request for the recent webhook delivery attempt log (bounded
+// to the last webhookMaxDeliveryLog entries across all webhooks).
+// responses:
+//	200: []meshmodelWebhookDeliveryResponseWrapper
+func (h *Handler) GetWebhookDeliveries(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Add("Content-Type", "application/json")
+
+	webhookDeliveryMu.Lock()
+	res := make([]WebhookDelivery, len(webhookDeliveries))
+	copy(res, webhookDeliveries)
+	webhookDeliveryMu.Unlock()
+
+	if err := json.NewEncoder(rw).Encode(res); err != nil {
+		h.log.Error(ErrGetMeshModels(err))
+		http.Error(rw, ErrGetMeshModels(err).Error(), http.StatusInternalServerError)
+	}
+}
+
+// validateWebhookURL rejects a webhook URL that isn't a plain http(s)
+// request to a public address: anything else either can't be delivered to
+// by sendWebhook's net/http client, or could be used to make this server
+// issue a request to its own internal network (loopback, RFC 1918/link-local
+// ranges, or the unspecified address) on an attacker's behalf. A hostname
+// is resolved and every returned address is checked, since it's the
+// resolved address a request actually reaches, not the literal hostname.
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf(`invalid webhook "url": %w`, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf(`webhook "url" must be http or https, got %q`, parsed.Scheme)
+	}
+	host := parsed.Hostname()
+	if host == "" {
+		return fmt.Errorf(`webhook "url" is missing a host`)
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if isDisallowedWebhookIP(ip) {
+			return fmt.Errorf("webhook url resolves to a disallowed address: %s", ip)
+		}
+		return nil
+	}
+
+	if strings.EqualFold(host, "localhost") {
+		return fmt.Errorf("webhook url must not target localhost")
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("error resolving webhook url host %q: %w", host, err)
+	}
+	for _, ip := range ips {
+		if isDisallowedWebhookIP(ip) {
+			return fmt.Errorf("webhook url host %q resolves to a disallowed address: %s", host, ip)
+		}
+	}
+	return nil
+}
+
+func isDisallowedWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// webhookHTTPClient is the client sendWebhook delivers every attempt
+// through. validateWebhookURL only runs once, at RegisterWebhook/
+// UpdateWebhook time - a webhook persists across restarts and is
+// redelivered to indefinitely, so a hostname that resolves to a public
+// address at registration time but a private/loopback one later (DNS
+// rebinding), or a target that 302s to one, would otherwise bypass that
+// check entirely. webhookDialContext re-resolves and re-checks the actual
+// address dialed on every connection this client makes, including ones
+// made to follow a redirect, and CheckRedirect additionally rejects a
+// redirect whose URL itself fails validateWebhookURL (e.g. a non-http(s)
+// scheme) before a connection is even attempted.
+var webhookHTTPClient = &http.Client{
+	Timeout: 30 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if err := validateWebhookURL(req.URL.String()); err != nil {
+			return fmt.Errorf("webhook redirect target rejected: %w", err)
+		}
+		return nil
+	},
+	Transport: &http.Transport{
+		DialContext: webhookDialContext,
+	},
+}
+
+// webhookDialContext resolves the host in addr itself (rather than trusting
+// the net.Dialer default resolution, which offers no hook to reject an
+// individual resolved address) and dials only an address that passes
+// isDisallowedWebhookIP, so a hostname that now resolves somewhere private
+// can't be connected to even if it passed validateWebhookURL at
+// registration time.
+func webhookDialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if isDisallowedWebhookIP(ip) {
+			return nil, fmt.Errorf("webhook address %s is disallowed", ip)
+		}
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var dialErr error
+	for _, resolved := range ips {
+		if isDisallowedWebhookIP(resolved.IP) {
+			continue
+		}
+		conn, err := (&net.Dialer{}).DialContext(ctx, network, net.JoinHostPort(resolved.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		dialErr = err
+	}
+	if dialErr == nil {
+		dialErr = fmt.Errorf("webhook host %q resolves only to disallowed addresses", host)
+	}
+	return nil, dialErr
+}
+
+// triggerWebhooks enqueues an asynchronous delivery to every registered
+// webhook subscribed to event. It is called from the registration
+// handlers (RegisterMeshmodelComponents, RegisterMeshmodels,
+// UpdateEntityStatus) right after they publish their watch event, so a
+// webhook delivery and a watch event always reflect the same mutation.
+func triggerWebhooks(event WebhookEventType, payload interface{}) {
+	ensureWebhooksLoaded()
+	body, err := json.Marshal(struct {
+		Event WebhookEventType `json:"event"`
+		Data  interface{}      `json:"data"`
+	}{Event: event, Data: payload})
+	if err != nil {
+		return
+	}
+
+	webhookMu.RLock()
+	var targets []*Webhook
+	for _, wh := range webhooks {
+		if webhookSubscribes(wh, event) {
+			targets = append(targets, wh)
+		}
+	}
+	webhookMu.RUnlock()
+
+	for _, wh := range targets {
+		go deliverWebhook(wh, event, body)
+	}
+}
+
+func webhookSubscribes(wh *Webhook, event WebhookEventType) bool {
+	for _, e := range wh.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// deliverWebhook POSTs body to wh.URL, retrying on a 5xx response or
+// transport error with exponential backoff, up to webhookMaxAttempts
+// times. Every attempt, successful or not, is recorded via recordDelivery.
+func deliverWebhook(wh *Webhook, event WebhookEventType, body []byte) {
+	backoff := time.Second
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		statusCode, err := sendWebhook(wh, body)
+		recordDelivery(wh.ID, event, attempt, statusCode, err)
+		if err == nil {
+			return
+		}
+		if attempt < webhookMaxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
+
+// sendWebhook performs a single delivery attempt via webhookHTTPClient -
+// which re-validates the resolved address of every connection it makes,
+// including redirect hops, against isDisallowedWebhookIP - and reports a
+// non-nil error for a transport failure (including a rejected address) or
+// a 5xx response, the two cases deliverWebhook retries.
+func sendWebhook(wh *Webhook, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, wh.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if wh.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(wh.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Meshery-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	if wh.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+wh.AuthToken)
+	}
+
+	resp, err := webhookHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return resp.StatusCode, fmt.Errorf("webhook target responded with %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func recordDelivery(webhookID string, event WebhookEventType, attempt, statusCode int, deliveryErr error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return
+	}
+	d := WebhookDelivery{
+		ID:          id.String(),
+		WebhookID:   webhookID,
+		Event:       event,
+		Attempt:     attempt,
+		StatusCode:  statusCode,
+		DeliveredAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if deliveryErr != nil {
+		d.Error = deliveryErr.Error()
+	}
+
+	webhookDeliveryMu.Lock()
+	webhookDeliveries = append(webhookDeliveries, d)
+	if len(webhookDeliveries) > webhookMaxDeliveryLog {
+		webhookDeliveries = webhookDeliveries[len(webhookDeliveries)-webhookMaxDeliveryLog:]
+	}
+	webhookDeliveryMu.Unlock()
+}
@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseGroupBy parses a comma-separated `groupBy` query parameter (e.g.
+// "category,model") into an ordered slice of DB columns, validating each
+// field against allowed the same way ParseSort does. Order is preserved so
+// callers can build a stable multi-column GROUP BY.
+func ParseGroupBy(raw string, allowed map[string]string) ([]string, error) {
+	var fields []string
+	if strings.TrimSpace(raw) == "" {
+		return fields, nil
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		column, ok := allowed[part]
+		if !ok {
+			return nil, fmt.Errorf("unknown groupBy field %q", part)
+		}
+		fields = append(fields, column)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortField is a single column in a multi-key ORDER BY clause, parsed from
+// a comma-separated `sort` query parameter (e.g. "category,-version").
+type SortField struct {
+	// Field is the underlying DB column name, resolved through the
+	// entity's allow-list.
+	Field string
+	// Descending is true when the field was prefixed with "-".
+	Descending bool
+}
+
+// ParseSort parses a comma-separated sort expression such as
+// "category,-version,displayName" into an ordered slice of SortFields.
+// Each field may be prefixed with "-" for descending order or "+" (or no
+// prefix) for ascending order. allowed maps the API-facing field name to
+// the DB column it resolves to; any field not present in allowed results
+// in an error so that callers can return a 400 instead of silently
+// ignoring or mis-sorting on an unknown/unsafe column.
+func ParseSort(raw string, allowed map[string]string) ([]SortField, error) {
+	var fields []SortField
+	if strings.TrimSpace(raw) == "" {
+		return fields, nil
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		descending := false
+		switch part[0] {
+		case '-':
+			descending = true
+			part = part[1:]
+		case '+':
+			part = part[1:]
+		}
+		part = strings.TrimSpace(part)
+
+		column, ok := allowed[part]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort field %q", part)
+		}
+		fields = append(fields, SortField{Field: column, Descending: descending})
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,15 @@
+package models
+
+// AggGroup is a single bucket returned by GetMeshmodelCardinality, keyed by
+// the distinct combination of groupBy field values its members share.
+type AggGroup struct {
+	Key   map[string]string `json:"key"`
+	Count int64             `json:"count"`
+}
+
+// MeshmodelCardinalityAPIResponse is returned by GET /api/meshmodels/cardinality.
+type MeshmodelCardinalityAPIResponse struct {
+	Groups      []AggGroup `json:"groups"`
+	TotalGroups int        `json:"totalGroups"`
+	TotalCount  int64      `json:"totalCount"`
+}